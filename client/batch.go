@@ -0,0 +1,110 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"ontrack-cli/config"
+)
+
+// Batch groups several mutations into one GraphQL document, each mutation
+// being identified by an alias.
+type Batch struct {
+	declarations []string
+	fields       []string
+	variables    map[string]interface{}
+	aliases      []string
+	labels       map[string]string
+}
+
+// BatchArg is an input field of a mutation in a batch
+type BatchArg struct {
+	// Name of the input field
+	Name string
+	// GraphQL type of the value
+	Type string
+	// Value of the input field
+	Value interface{}
+}
+
+func NewBatch() *Batch {
+	return &Batch{
+		variables: make(map[string]interface{}),
+		labels:    make(map[string]string),
+	}
+}
+
+// Add adds a mutation to the batch and returns its alias. The label is used
+// to identify the mutation in error messages.
+func (b *Batch) Add(label string, mutation string, args ...BatchArg) string {
+	alias := fmt.Sprintf("m%d", len(b.aliases))
+	b.labels[alias] = label
+	var inputs []string
+	for _, arg := range args {
+		variable := alias + "_" + arg.Name
+		b.declarations = append(b.declarations, "$"+variable+": "+arg.Type)
+		inputs = append(inputs, arg.Name+": $"+variable)
+		b.variables[variable] = arg.Value
+	}
+	b.fields = append(b.fields, fmt.Sprintf(
+		"%s: %s(input: {%s}) { errors { message } }",
+		alias,
+		mutation,
+		strings.Join(inputs, ", "),
+	))
+	b.aliases = append(b.aliases, alias)
+	return alias
+}
+
+// Len returns the number of mutations in the batch
+func (b *Batch) Len() int {
+	return len(b.aliases)
+}
+
+// Run sends all the mutations in one call and returns the errors reported by each of them, indexed by alias.
+// The returned error is set only if the call itself failed.
+func (b *Batch) Run(cfg *config.Config) (map[string]error, error) {
+	result := make(map[string]error)
+	if len(b.aliases) == 0 {
+		return result, nil
+	}
+
+	query := fmt.Sprintf(
+		"mutation Batch(%s) {\n%s\n}",
+		strings.Join(b.declarations, ", "),
+		strings.Join(b.fields, "\n"),
+	)
+
+	// alias --> errors --> []error
+	var data map[string]struct {
+		Errors []struct {
+			Message string
+		}
+	}
+	if err := GraphQLCall(cfg, query, b.variables, &data); err != nil {
+		return nil, err
+	}
+
+	for _, alias := range b.aliases {
+		if err := CheckDataErrors(data[alias].Errors); err != nil {
+			result[alias] = err
+		}
+	}
+	return result, nil
+}
+
+// CheckErrors aggregates the errors returned by Run into one error, in the order
+// of the mutations, or returns nil if there is no error.
+func (b *Batch) CheckErrors(batchErrors map[string]error) error {
+	var message string
+	for _, alias := range b.aliases {
+		if err, ok := batchErrors[alias]; ok {
+			message += fmt.Sprintf("%s:\n%s", b.labels[alias], err.Error())
+		}
+	}
+	if message != "" {
+		return errors.New(message)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"ontrack-cli/config"
+)
+
+// buildByName selects a build using its name in a branch, through the project search form, the root
+// builds field having no name argument. The query must declare the $project, $branch and $build variables,
+// set by buildByNameVariables, and select the buildKeyFields.
+const buildByName = `builds(
+	project: $project,
+	buildProjectFilter: {branchName: $branch, buildName: $build, buildExactMatch: true}
+)`
+
+// GraphQL fields identifying a build selected by buildByName
+const buildKeyFields = `
+	name
+	branch {
+		name
+	}
+`
+
+// buildByNameVariables returns the variables of buildByName, the branch name being
+// a regular expression in the search form
+func buildByNameVariables(project string, branch string, build string) map[string]interface{} {
+	return map[string]interface{}{
+		"project": project,
+		"branch":  "^" + regexp.QuoteMeta(branch) + "$",
+		"build":   build,
+	}
+}
+
+// buildKey identifies a build selected by buildByName
+type buildKey struct {
+	Name   string
+	Branch struct {
+		Name string
+	}
+}
+
+func (k buildKey) key() buildKey {
+	return k
+}
+
+// findBuildByName returns the build having exactly this name in the branch, nil if not found
+func findBuildByName[B interface{ key() buildKey }](builds []B, branch string, build string) *B {
+	for index := range builds {
+		key := builds[index].key()
+		if key.Name == build && key.Branch.Name == branch {
+			return &builds[index]
+		}
+	}
+	return nil
+}
+
+// Build is a build with its properties, promotions, validations and links
+type Build struct {
+	ID          int       `json:"id" yaml:"id"`
+	Name        string    `json:"name" yaml:"name"`
+	Description string    `json:"description" yaml:"description"`
+	Project     string    `json:"project" yaml:"project"`
+	Branch      string    `json:"branch" yaml:"branch"`
+	Creation    Signature `json:"creation" yaml:"creation"`
+	RunInfo     *RunInfo  `json:"runInfo,omitempty" yaml:"runInfo,omitempty"`
+	// Values of the release and Git commit properties, if set
+	Release    string     `json:"release,omitempty" yaml:"release,omitempty"`
+	Commit     string     `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Properties []Property `json:"properties" yaml:"properties"`
+	// Promotion runs, the most recent first
+	PromotionRuns []PromotionRun `json:"promotionRuns" yaml:"promotionRuns"`
+	// Last validation run for each validation stamp, sorted by validation stamp name
+	ValidationRuns []ValidationRun `json:"validationRuns" yaml:"validationRuns"`
+	// Builds used by this build
+	Using []BuildLink `json:"using" yaml:"using"`
+	// Builds using this build
+	UsedBy []BuildLink `json:"usedBy" yaml:"usedBy"`
+}
+
+// BuildLink is a build linked to another one
+type BuildLink struct {
+	ID      int    `json:"id" yaml:"id"`
+	Project string `json:"project" yaml:"project"`
+	Branch  string `json:"branch" yaml:"branch"`
+	Name    string `json:"name" yaml:"name"`
+}
+
+// GraphQL fields for a linked build
+const buildLinkFields = `
+	pageItems {
+		id
+		name
+		branch {
+			name
+			project {
+				name
+			}
+		}
+	}
+`
+
+// GetBuild returns a build using its name in a branch
+func GetBuild(cfg *config.Config, project string, branch string, build string) (*Build, error) {
+	builds, err := getBuilds(cfg, `$project: String!, $branch: String!, $build: String!`, buildByName, buildByNameVariables(project, branch, build))
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range builds {
+		if result.Name == build && result.Branch == branch {
+			return result, nil
+		}
+	}
+	if cfg.Disabled {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("build %s %w in %s/%s", build, ErrNotFound, project, branch)
+}
+
+// GetBuildByID returns a build using its ID
+func GetBuildByID(cfg *config.Config, id int) (*Build, error) {
+	builds, err := getBuilds(cfg, `$id: Int!`, `builds(id: $id)`, map[string]interface{}{
+		"id": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(builds) > 0 {
+		return builds[0], nil
+	}
+	if cfg.Disabled {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("build %d %w", id, ErrNotFound)
+}
+
+// getBuilds returns the builds selected by a builds field, using the given variable declarations
+func getBuilds(cfg *config.Config, declarations string, selection string, variables map[string]interface{}) ([]*Build, error) {
+	type linkData struct {
+		PageItems []struct {
+			ID     int
+			Name   string
+			Branch struct {
+				Name    string
+				Project struct {
+					Name string
+				}
+			}
+		}
+	}
+	var data struct {
+		Builds []struct {
+			ID          int
+			Name        string
+			Description string
+			Creation    Signature
+			RunInfo     *RunInfo
+			Branch      struct {
+				Name    string
+				Project struct {
+					Name string
+				}
+			}
+			Properties     []propertyData
+			PromotionRuns  []PromotionRun
+			ValidationRuns []ValidationRun
+			Using          linkData
+			UsedBy         linkData
+		}
+	}
+
+	if err := GraphQLCall(cfg, `
+		query Build(`+declarations+`) {
+			`+selection+` {
+				id
+				name
+				description
+				creation {
+					user
+					time
+				}
+				runInfo {
+					sourceType
+					sourceUri
+					triggerType
+					triggerData
+					runTime
+				}
+				branch {
+					name
+					project {
+						name
+					}
+				}
+				properties {
+					type {
+						typeName
+					}
+					value
+				}
+				promotionRuns {
+					`+promotionRunFields+`
+				}
+				validationRuns(count: 500) {
+					`+validationRunFields+`
+				}
+				using(size: 100) {
+					`+buildLinkFields+`
+				}
+				usedBy(size: 100) {
+					`+buildLinkFields+`
+				}
+			}
+		}
+	`, variables, &data); err != nil {
+		return nil, err
+	}
+
+	links := func(items linkData) []BuildLink {
+		var result []BuildLink
+		for _, item := range items.PageItems {
+			result = append(result, BuildLink{
+				ID:      item.ID,
+				Project: item.Branch.Project.Name,
+				Branch:  item.Branch.Name,
+				Name:    item.Name,
+			})
+		}
+		return result
+	}
+
+	var builds []*Build
+	for _, node := range data.Builds {
+		build := &Build{
+			ID:             node.ID,
+			Name:           node.Name,
+			Description:    node.Description,
+			Project:        node.Branch.Project.Name,
+			Branch:         node.Branch.Name,
+			Creation:       node.Creation,
+			RunInfo:        node.RunInfo,
+			Properties:     properties(node.Properties),
+			PromotionRuns:  node.PromotionRuns,
+			ValidationRuns: lastValidationRuns(node.ValidationRuns),
+			Using:          links(node.Using),
+			UsedBy:         links(node.UsedBy),
+		}
+
+		// Release & commit
+		var release struct {
+			Name string
+		}
+		if value := FindProperty(build.Properties, ReleasePropertyType); value != nil && json.Unmarshal(value, &release) == nil {
+			build.Release = release.Name
+		}
+		var commit struct {
+			Commit string
+		}
+		if value := FindProperty(build.Properties, GitCommitPropertyType); value != nil && json.Unmarshal(value, &commit) == nil {
+			build.Commit = commit.Commit
+		}
+
+		builds = append(builds, build)
+	}
+	return builds, nil
+}
+
+// lastValidationRuns returns the most recent run for each validation stamp, sorted by validation stamp name
+func lastValidationRuns(runs []ValidationRun) []ValidationRun {
+	last := make(map[string]ValidationRun)
+	for _, run := range runs {
+		if previous, ok := last[run.ValidationStamp.Name]; !ok || run.RunOrder > previous.RunOrder {
+			last[run.ValidationStamp.Name] = run
+		}
+	}
+	var result []ValidationRun
+	for _, run := range last {
+		result = append(result, run)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ValidationStamp.Name < result[j].ValidationStamp.Name
+	})
+	return result
+}
@@ -9,6 +9,9 @@ import (
 	resty "github.com/go-resty/resty/v2"
 )
 
+// ErrNotFound is wrapped by the errors returned when a resource does not exist in Ontrack
+var ErrNotFound = errors.New("not found")
+
 // GraphQLCall performs a GraphQL query/mutation to Ontrack
 func GraphQLCall(cfg *config.Config, query string, variables map[string]interface{}, data interface{}) error {
 
@@ -22,13 +25,7 @@ func GraphQLCall(cfg *config.Config, query string, variables map[string]interfac
 		"variables": variables,
 	}
 
-	client := resty.New()
-	client.SetDebug(config.GraphQLLogging)
-	if cfg.Token != "" {
-		client.SetHeader("X-Ontrack-Token", cfg.Token)
-	} else if cfg.Username != "" {
-		client.SetBasicAuth(cfg.Username, cfg.Password)
-	}
+	client := newRestClient(cfg)
 
 	resp, err := client.R().
 		SetHeader("Content-Type", "application/json").
@@ -72,6 +69,18 @@ func GraphQLCall(cfg *config.Config, query string, variables map[string]interfac
 	return nil
 }
 
+// newRestClient returns an HTTP client authenticated for the Ontrack server
+func newRestClient(cfg *config.Config) *resty.Client {
+	client := resty.New()
+	client.SetDebug(config.GraphQLLogging)
+	if cfg.Token != "" {
+		client.SetHeader("X-Ontrack-Token", cfg.Token)
+	} else if cfg.Username != "" {
+		client.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	return client
+}
+
 type graphErr struct {
 	Message string
 }
@@ -0,0 +1,386 @@
+package client
+
+import (
+	"encoding/json"
+
+	"ontrack-cli/config"
+)
+
+// FQCN of the property types which can be set by dedicated mutations
+const (
+	GitHubPropertyType              = "net.nemerosa.ontrack.extension.github.property.GitHubProjectConfigurationPropertyType"
+	BitbucketCloudPropertyType      = "net.nemerosa.ontrack.extension.bitbucket.cloud.property.BitbucketCloudProjectConfigurationPropertyType"
+	AutoValidationStampPropertyType = "net.nemerosa.ontrack.extension.general.AutoValidationStampPropertyType"
+	AutoPromotionLevelPropertyType  = "net.nemerosa.ontrack.extension.general.AutoPromotionLevelPropertyType"
+	GitBranchPropertyType           = "net.nemerosa.ontrack.extension.git.property.GitBranchConfigurationPropertyType"
+	GitCommitPropertyType           = "net.nemerosa.ontrack.extension.git.property.GitCommitPropertyType"
+	ReleasePropertyType             = "net.nemerosa.ontrack.extension.general.ReleasePropertyType"
+)
+
+// Property is a property set on an entity
+type Property struct {
+	// FQCN of the property type
+	Type  string          `json:"type" yaml:"type"`
+	Value json.RawMessage `json:"value" yaml:"value"`
+}
+
+// MarshalYAML renders the value as YAML instead of a list of bytes
+func (p Property) MarshalYAML() (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(p.Value, &value); err != nil {
+		return nil, err
+	}
+	return struct {
+		Type  string      `yaml:"type"`
+		Value interface{} `yaml:"value"`
+	}{p.Type, value}, nil
+}
+
+// Project is a project with its properties and its branches
+type Project struct {
+	ID         int
+	Name       string
+	Properties []Property
+	Branches   []Branch
+}
+
+// Branch is a branch with its properties
+type Branch struct {
+	ID         int
+	Name       string
+	Properties []Property
+}
+
+// FindProperty returns the value of a property, nil if not set
+func FindProperty(properties []Property, propertyType string) json.RawMessage {
+	for _, property := range properties {
+		if property.Type == propertyType {
+			return property.Value
+		}
+	}
+	return nil
+}
+
+// propertyData is a property as returned by GraphQL
+type propertyData struct {
+	Type struct {
+		TypeName string
+	}
+	Value json.RawMessage
+}
+
+// properties keeps only the properties having a value
+func properties(items []propertyData) []Property {
+	var result []Property
+	for _, item := range items {
+		if len(item.Value) > 0 && string(item.Value) != "null" {
+			result = append(result, Property{Type: item.Type.TypeName, Value: item.Value})
+		}
+	}
+	return result
+}
+
+// GetProject returns a project with its properties and its branches, or nil if it does not exist
+func GetProject(cfg *config.Config, name string) (*Project, error) {
+	var data struct {
+		Projects []struct {
+			ID         int
+			Name       string
+			Properties []propertyData
+			Branches   []struct {
+				ID         int
+				Name       string
+				Properties []propertyData
+			}
+		}
+	}
+
+	if err := GraphQLCall(cfg, `
+		query Project($project: String!) {
+			projects(name: $project) {
+				id
+				name
+				properties {
+					type {
+						typeName
+					}
+					value
+				}
+				branches {
+					id
+					name
+					properties {
+						type {
+							typeName
+						}
+						value
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project": name,
+	}, &data); err != nil {
+		return nil, err
+	}
+
+	if len(data.Projects) == 0 {
+		return nil, nil
+	}
+
+	node := data.Projects[0]
+	project := &Project{ID: node.ID, Name: node.Name, Properties: properties(node.Properties)}
+	for _, branch := range node.Branches {
+		project.Branches = append(project.Branches, Branch{ID: branch.ID, Name: branch.Name, Properties: properties(branch.Properties)})
+	}
+	return project, nil
+}
+
+// CreateProjectOrGet creates a project if it does not exist yet
+func CreateProjectOrGet(cfg *config.Config, project string) error {
+	var data struct {
+		CreateProjectOrGet struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation CreateProjectOrGet($project: String!) {
+			createProjectOrGet(input: {name: $project}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project": project,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.CreateProjectOrGet.Errors)
+}
+
+// CreateBranchOrGet creates a branch if it does not exist yet. The project must exist.
+func CreateBranchOrGet(cfg *config.Config, project string, branch string) error {
+	var data struct {
+		CreateBranchOrGet struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation CreateBranchOrGet($project: String!, $branch: String!) {
+			createBranchOrGet(input: {projectName: $project, name: $branch}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project": project,
+		"branch":  branch,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.CreateBranchOrGet.Errors)
+}
+
+// SetProjectGitHubProperty configures a project to use a GitHub repository
+func SetProjectGitHubProperty(
+	cfg *config.Config,
+	project string,
+	configuration string,
+	repository string,
+	indexation int,
+	issueService string,
+) error {
+	var data struct {
+		SetProjectGitHubConfigurationProperty struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation SetProjectGitHubProperty(
+			$project: String!,
+			$configuration: String!,
+			$repository: String!,
+			$indexationInterval: Int,
+			$issueServiceConfigurationIdentifier: String
+		) {
+			setProjectGitHubConfigurationProperty(input: {
+				project: $project,
+				configuration: $configuration,
+				repository: $repository,
+				indexationInterval: $indexationInterval,
+				issueServiceConfigurationIdentifier: $issueServiceConfigurationIdentifier
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":                             project,
+		"configuration":                       configuration,
+		"repository":                          repository,
+		"indexationInterval":                  indexation,
+		"issueServiceConfigurationIdentifier": issueService,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.SetProjectGitHubConfigurationProperty.Errors)
+}
+
+// SetProjectBitbucketCloudProperty configures a project to use a Bitbucket Cloud repository
+func SetProjectBitbucketCloudProperty(
+	cfg *config.Config,
+	project string,
+	configuration string,
+	repository string,
+	indexation int,
+	issueService string,
+) error {
+	var data struct {
+		SetProjectBitbucketCloudConfigurationProperty struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation SetProjectBitbucketCloudConfigurationProperty(
+			$project: String!,
+			$configuration: String!,
+			$repository: String!,
+			$indexationInterval: Int,
+			$issueServiceConfigurationIdentifier: String
+		) {
+			setProjectBitbucketCloudConfigurationProperty(input: {
+				project: $project,
+				configuration: $configuration,
+				repository: $repository,
+				indexationInterval: $indexationInterval,
+				issueServiceConfigurationIdentifier: $issueServiceConfigurationIdentifier
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":                             project,
+		"configuration":                       configuration,
+		"repository":                          repository,
+		"indexationInterval":                  indexation,
+		"issueServiceConfigurationIdentifier": issueService,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.SetProjectBitbucketCloudConfigurationProperty.Errors)
+}
+
+// SetProjectAutoValidationStampProperty sets the auto creation of validation stamps on a project
+func SetProjectAutoValidationStampProperty(cfg *config.Config, project string, autoCreate bool, autoCreateIfNotPredefined bool) error {
+	var data struct {
+		SetProjectAutoValidationStampProperty struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation SetProjectAutoValidationStampProperty(
+			$project: String!,
+			$autoCreate: Boolean!,
+			$autoCreateIfNotPredefined: Boolean!
+		) {
+			setProjectAutoValidationStampProperty(input: {
+				project: $project,
+				isAutoCreate: $autoCreate,
+				isAutoCreateIfNotPredefined: $autoCreateIfNotPredefined
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":                   project,
+		"autoCreate":                autoCreate,
+		"autoCreateIfNotPredefined": autoCreateIfNotPredefined,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.SetProjectAutoValidationStampProperty.Errors)
+}
+
+// SetProjectAutoPromotionLevelProperty sets the auto creation of promotion levels on a project
+func SetProjectAutoPromotionLevelProperty(cfg *config.Config, project string, autoCreate bool) error {
+	var data struct {
+		SetProjectAutoPromotionLevelProperty struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation SetProjectAutoPromotionLevelProperty(
+			$project: String!,
+			$autoCreate: Boolean!
+		) {
+			setProjectAutoPromotionLevelProperty(input: {
+				project: $project,
+				isAutoCreate: $autoCreate
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":    project,
+		"autoCreate": autoCreate,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.SetProjectAutoPromotionLevelProperty.Errors)
+}
+
+// SetBranchGitProperty associates a branch with a Git branch
+func SetBranchGitProperty(cfg *config.Config, project string, branch string, gitBranch string) error {
+	var data struct {
+		SetBranchGitConfigProperty struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation SetBranchGitConfigProperty(
+			$project: String!,
+			$branch: String!,
+			$gitBranch: String!
+		) {
+			setBranchGitConfigProperty(input: {
+				project: $project,
+				branch: $branch,
+				gitBranch: $gitBranch
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":   project,
+		"branch":    branch,
+		"gitBranch": gitBranch,
+	}, &data); err != nil {
+		return err
+	}
+	return CheckDataErrors(data.SetBranchGitConfigProperty.Errors)
+}
@@ -1,6 +1,11 @@
 package client
 
-import "ontrack-cli/config"
+import (
+	"encoding/json"
+	"fmt"
+
+	"ontrack-cli/config"
+)
 
 func SetupPromotionLevel(
 	cfg *config.Config,
@@ -91,3 +96,216 @@ func SetupPromotionLevel(
 	// OK
 	return nil
 }
+
+// AutoPromotionPropertyType is the FQCN of the auto-promotion property of promotion levels
+const AutoPromotionPropertyType = "net.nemerosa.ontrack.extension.general.AutoPromotionPropertyType"
+
+// AutoPromotion defines the criteria for a build to be promoted automatically
+type AutoPromotion struct {
+	ValidationStamps []string `json:"validationStamps" yaml:"validationStamps"`
+	Include          string   `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude          string   `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	PromotionLevels  []string `json:"promotionLevels" yaml:"promotionLevels"`
+}
+
+// PromotionLevel is a promotion level of a branch, with its auto-promotion criteria
+type PromotionLevel struct {
+	ID            int            `json:"id" yaml:"id"`
+	Name          string         `json:"name" yaml:"name"`
+	Description   string         `json:"description" yaml:"description"`
+	Image         bool           `json:"image" yaml:"image"`
+	AutoPromotion *AutoPromotion `json:"autoPromotion,omitempty" yaml:"autoPromotion,omitempty"`
+}
+
+// BranchPromotionLevels are the promotion levels of a branch, in their order
+type BranchPromotionLevels struct {
+	BranchID        int
+	PromotionLevels []PromotionLevel
+}
+
+// GetPromotionLevels returns the promotion levels of a branch, in their order.
+// If the configuration is disabled, nil is returned.
+func GetPromotionLevels(
+	cfg *config.Config,
+	project string,
+	branch string,
+) (*BranchPromotionLevels, error) {
+
+	var data struct {
+		Branches []struct {
+			ID              int
+			PromotionLevels []struct {
+				ID          int
+				Name        string
+				Description string
+				Image       bool
+				Properties  []struct {
+					Value json.RawMessage
+				}
+			}
+		}
+	}
+
+	if err := GraphQLCall(cfg, `
+		query PromotionLevels(
+			$project: String!,
+			$branch: String!,
+			$propertyType: String!
+		) {
+			branches(project: $project, name: $branch) {
+				id
+				promotionLevels {
+					id
+					name
+					description
+					image
+					properties(type: $propertyType) {
+						value
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":      project,
+		"branch":       branch,
+		"propertyType": AutoPromotionPropertyType,
+	}, &data); err != nil {
+		return nil, err
+	}
+
+	if cfg.Disabled {
+		return nil, nil
+	}
+	if len(data.Branches) == 0 {
+		return nil, fmt.Errorf("branch %s %w in %s", branch, ErrNotFound, project)
+	}
+
+	result := &BranchPromotionLevels{
+		BranchID: data.Branches[0].ID,
+	}
+	for _, item := range data.Branches[0].PromotionLevels {
+		level := PromotionLevel{
+			ID:          item.ID,
+			Name:        item.Name,
+			Description: item.Description,
+			Image:       item.Image,
+		}
+		for _, property := range item.Properties {
+			autoPromotion, err := parseAutoPromotion(property.Value)
+			if err != nil {
+				return nil, fmt.Errorf("auto-promotion of %s: %w", item.Name, err)
+			}
+			level.AutoPromotion = autoPromotion
+		}
+		result.PromotionLevels = append(result.PromotionLevels, level)
+	}
+	return result, nil
+}
+
+// GetPromotionLevel returns a promotion level using its name, or an error if not found.
+// If the configuration is disabled, nil is returned.
+func GetPromotionLevel(
+	cfg *config.Config,
+	project string,
+	branch string,
+	name string,
+) (*PromotionLevel, error) {
+	levels, err := GetPromotionLevels(cfg, project, branch)
+	if err != nil || levels == nil {
+		return nil, err
+	}
+	for index := range levels.PromotionLevels {
+		if levels.PromotionLevels[index].Name == name {
+			return &levels.PromotionLevels[index], nil
+		}
+	}
+	return nil, fmt.Errorf("promotion level %s %w in %s/%s", name, ErrNotFound, project, branch)
+}
+
+// parseAutoPromotion reads the value of the auto-promotion property, where the validation stamps
+// and promotion levels are either names or objects with a name.
+func parseAutoPromotion(value json.RawMessage) (*AutoPromotion, error) {
+	if len(value) == 0 || string(value) == "null" {
+		return nil, nil
+	}
+	var raw struct {
+		ValidationStamps []json.RawMessage
+		Include          string
+		Exclude          string
+		PromotionLevels  []json.RawMessage
+	}
+	if err := json.Unmarshal(value, &raw); err != nil {
+		return nil, err
+	}
+	validationStamps, err := entityNames(raw.ValidationStamps)
+	if err != nil {
+		return nil, err
+	}
+	promotionLevels, err := entityNames(raw.PromotionLevels)
+	if err != nil {
+		return nil, err
+	}
+	return &AutoPromotion{
+		ValidationStamps: validationStamps,
+		Include:          raw.Include,
+		Exclude:          raw.Exclude,
+		PromotionLevels:  promotionLevels,
+	}, nil
+}
+
+func entityNames(items []json.RawMessage) ([]string, error) {
+	names := []string{}
+	for _, item := range items {
+		var name string
+		if err := json.Unmarshal(item, &name); err == nil {
+			names = append(names, name)
+			continue
+		}
+		var entity struct {
+			Name string
+		}
+		if err := json.Unmarshal(item, &entity); err != nil {
+			return nil, err
+		}
+		names = append(names, entity.Name)
+	}
+	return names, nil
+}
+
+// DeletePromotionLevel deletes a promotion level and all its promotion runs
+func DeletePromotionLevel(cfg *config.Config, id int) error {
+	var data struct {
+		DeletePromotionLevelById struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation DeletePromotionLevel($id: Int!) {
+			deletePromotionLevelById(input: {id: $id}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+
+	return CheckDataErrors(data.DeletePromotionLevelById.Errors)
+}
+
+// ReorderPromotionLevels sets the order of the promotion levels of a branch
+func ReorderPromotionLevels(cfg *config.Config, branchID int, ids []int) error {
+	return RestPut(cfg, fmt.Sprintf("/rest/structure/branches/%d/promotionLevels/reorder", branchID), map[string]interface{}{
+		"ids": ids,
+	})
+}
+
+// SetPromotionLevelImage uploads the image of a promotion level
+func SetPromotionLevelImage(cfg *config.Config, id int, file string) error {
+	return UploadImage(cfg, fmt.Sprintf("/rest/structure/promotionLevels/%d/image", id), file)
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"fmt"
+
+	"ontrack-cli/config"
+)
+
+// PromotionRun is the promotion of a build
+type PromotionRun struct {
+	ID             int       `json:"id" yaml:"id"`
+	Description    string    `json:"description" yaml:"description"`
+	Creation       Signature `json:"creation" yaml:"creation"`
+	PromotionLevel struct {
+		Name string `json:"name" yaml:"name"`
+	} `json:"promotionLevel" yaml:"promotionLevel"`
+	Build struct {
+		ID   int    `json:"id" yaml:"id"`
+		Name string `json:"name" yaml:"name"`
+	} `json:"build" yaml:"build"`
+}
+
+// GraphQL fields for a promotion run
+const promotionRunFields = `
+	id
+	description
+	creation {
+		user
+		time
+	}
+	promotionLevel {
+		name
+	}
+	build {
+		id
+		name
+	}
+`
+
+// GetBuildPromotionRuns returns the promotion runs of a build, the most recent first.
+// If promotion is not empty, only the runs for this promotion level are returned.
+func GetBuildPromotionRuns(
+	cfg *config.Config,
+	project string,
+	branch string,
+	build string,
+	promotion string,
+) ([]PromotionRun, error) {
+
+	var data struct {
+		Builds []struct {
+			buildKey
+			PromotionRuns []PromotionRun
+		}
+	}
+
+	variables := buildByNameVariables(project, branch, build)
+	if promotion != "" {
+		variables["promotion"] = promotion
+	}
+
+	if err := GraphQLCall(cfg, `
+		query BuildPromotionRuns(
+			$project: String!,
+			$branch: String!,
+			$build: String!,
+			$promotion: String
+		) {
+			`+buildByName+` {
+				`+buildKeyFields+`
+				promotionRuns(promotion: $promotion) {
+					`+promotionRunFields+`
+				}
+			}
+		}
+	`, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if cfg.Disabled {
+		return nil, nil
+	}
+	node := findBuildByName(data.Builds, branch, build)
+	if node == nil {
+		return nil, fmt.Errorf("build %s %w in %s/%s", build, ErrNotFound, project, branch)
+	}
+
+	// Exact match on the promotion level name
+	var runs []PromotionRun
+	for _, run := range node.PromotionRuns {
+		if promotion == "" || run.PromotionLevel.Name == promotion {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// GetPromotionLevelRuns returns the last promotion runs for a promotion level, the most recent first
+func GetPromotionLevelRuns(
+	cfg *config.Config,
+	project string,
+	branch string,
+	promotion string,
+	count int,
+) ([]PromotionRun, error) {
+
+	var data struct {
+		Branches []struct {
+			PromotionLevels []struct {
+				Name          string
+				PromotionRuns []PromotionRun
+			}
+		}
+	}
+
+	if err := GraphQLCall(cfg, `
+		query PromotionLevelRuns(
+			$project: String!,
+			$branch: String!,
+			$count: Int
+		) {
+			branches(project: $project, name: $branch) {
+				promotionLevels {
+					name
+					promotionRuns(first: $count) {
+						`+promotionRunFields+`
+					}
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project": project,
+		"branch":  branch,
+		"count":   count,
+	}, &data); err != nil {
+		return nil, err
+	}
+
+	if cfg.Disabled {
+		return nil, nil
+	}
+	if len(data.Branches) == 0 {
+		return nil, fmt.Errorf("branch %s %w in %s", branch, ErrNotFound, project)
+	}
+	for _, level := range data.Branches[0].PromotionLevels {
+		if level.Name == promotion {
+			return level.PromotionRuns, nil
+		}
+	}
+	return nil, fmt.Errorf("promotion level %s %w in %s/%s", promotion, ErrNotFound, project, branch)
+}
+
+// DeletePromotionRun deletes a promotion run
+func DeletePromotionRun(cfg *config.Config, id int) error {
+	var data struct {
+		DeletePromotionRun struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation DeletePromotionRun($id: Int!) {
+			deletePromotionRun(input: {promotionRunId: $id}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+
+	return CheckDataErrors(data.DeletePromotionRun.Errors)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"fmt"
+
+	"ontrack-cli/config"
+)
+
+// MetaInfoPropertyType is the FQCN of the meta information property
+const MetaInfoPropertyType = "net.nemerosa.ontrack.extension.general.MetaInfoPropertyType"
+
+// GetProjectMetaInfo returns the value of an item of the meta information property of a project.
+// If the configuration is disabled, an empty value is returned.
+func GetProjectMetaInfo(cfg *config.Config, project string, name string) (string, error) {
+	var data struct {
+		Projects []struct {
+			Properties []struct {
+				Value *struct {
+					Items []struct {
+						Name  string
+						Value string
+					}
+				}
+			}
+		}
+	}
+
+	if err := GraphQLCall(cfg, `
+		query ProjectMetaInfo(
+			$project: String!,
+			$propertyType: String!
+		) {
+			projects(name: $project) {
+				properties(type: $propertyType) {
+					value
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":      project,
+		"propertyType": MetaInfoPropertyType,
+	}, &data); err != nil {
+		return "", err
+	}
+
+	if cfg.Disabled {
+		return "", nil
+	}
+	if len(data.Projects) == 0 {
+		return "", fmt.Errorf("project %s %w", project, ErrNotFound)
+	}
+	for _, property := range data.Projects[0].Properties {
+		if property.Value == nil {
+			continue
+		}
+		for _, item := range property.Value.Items {
+			if item.Name == name {
+				return item.Value, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("meta information %s %w on project %s", name, ErrNotFound, project)
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"fmt"
+
+	config "ontrack-cli/config"
+)
+
+// RestPut sends some JSON to the REST API of Ontrack.
+// The path is relative to the URL of the server, like /rest/structure/branches/1/promotionLevels/reorder
+func RestPut(cfg *config.Config, path string, body interface{}) error {
+
+	// If config is disabled, skips the call
+	if cfg.Disabled {
+		return nil
+	}
+
+	resp, err := newRestClient(cfg).R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body).
+		Put(cfg.URL + path)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("HTTP %d %s", resp.StatusCode(), resp.String())
+	}
+
+	// OK
+	return nil
+}
+
+// UploadImage uploads an image file for an entity, using the REST API of Ontrack.
+// The path is relative to the URL of the server, like /rest/structure/validationStamps/1/image
+func UploadImage(cfg *config.Config, path string, file string) error {
+
+	// If config is disabled, skips the call
+	if cfg.Disabled {
+		return nil
+	}
+
+	resp, err := newRestClient(cfg).R().
+		SetFile("file", file).
+		Put(cfg.URL + path)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("HTTP %d %s", resp.StatusCode(), resp.String())
+	}
+
+	// OK
+	return nil
+}
@@ -0,0 +1,286 @@
+package client
+
+import (
+	"fmt"
+
+	"ontrack-cli/config"
+)
+
+// Signature of the creation of an entity
+type Signature struct {
+	User string `json:"user" yaml:"user"`
+	Time string `json:"time" yaml:"time"`
+}
+
+// ValidationRunStatus is one entry in the status history of a validation run
+type ValidationRunStatus struct {
+	StatusID struct {
+		ID   string `json:"id" yaml:"id"`
+		Name string `json:"name" yaml:"name"`
+	} `json:"statusID" yaml:"statusID"`
+	Description string    `json:"description" yaml:"description"`
+	Creation    Signature `json:"creation" yaml:"creation"`
+}
+
+// ValidationRunData is the typed data associated with a validation run
+type ValidationRunData struct {
+	Descriptor struct {
+		ID string `json:"id" yaml:"id"`
+	} `json:"descriptor" yaml:"descriptor"`
+	Data interface{} `json:"data" yaml:"data"`
+}
+
+// ValidationRun is a validation of a build
+type ValidationRun struct {
+	ID              int       `json:"id" yaml:"id"`
+	RunOrder        int       `json:"runOrder" yaml:"runOrder"`
+	Description     string    `json:"description" yaml:"description"`
+	Creation        Signature `json:"creation" yaml:"creation"`
+	ValidationStamp struct {
+		Name string `json:"name" yaml:"name"`
+	} `json:"validationStamp" yaml:"validationStamp"`
+	Build struct {
+		ID     int    `json:"id" yaml:"id"`
+		Name   string `json:"name" yaml:"name"`
+		Branch struct {
+			Name    string `json:"name" yaml:"name"`
+			Project struct {
+				Name string `json:"name" yaml:"name"`
+			} `json:"project" yaml:"project"`
+		} `json:"branch" yaml:"branch"`
+	} `json:"build" yaml:"build"`
+	LastStatus            ValidationRunStatus   `json:"lastStatus" yaml:"lastStatus"`
+	ValidationRunStatuses []ValidationRunStatus `json:"validationRunStatuses" yaml:"validationRunStatuses"`
+	Data                  *ValidationRunData    `json:"data,omitempty" yaml:"data,omitempty"`
+	RunInfo               *RunInfo              `json:"runInfo,omitempty" yaml:"runInfo,omitempty"`
+}
+
+// GraphQL fields for a validation run
+const validationRunFields = `
+	id
+	runOrder
+	description
+	creation {
+		user
+		time
+	}
+	validationStamp {
+		name
+	}
+	build {
+		id
+		name
+		branch {
+			name
+			project {
+				name
+			}
+		}
+	}
+	lastStatus {
+		statusID {
+			id
+			name
+		}
+		description
+		creation {
+			user
+			time
+		}
+	}
+	validationRunStatuses {
+		statusID {
+			id
+			name
+		}
+		description
+		creation {
+			user
+			time
+		}
+	}
+	data {
+		descriptor {
+			id
+		}
+		data
+	}
+	runInfo {
+		sourceType
+		sourceUri
+		triggerType
+		triggerData
+		runTime
+	}
+`
+
+// GetValidationRuns returns the validation runs of a build, the most recent first.
+// If validationStamp is not empty, only the runs for this validation stamp are returned.
+func GetValidationRuns(
+	cfg *config.Config,
+	project string,
+	branch string,
+	build string,
+	validationStamp string,
+	count int,
+) ([]ValidationRun, error) {
+
+	var data struct {
+		Builds []struct {
+			buildKey
+			ValidationRuns []ValidationRun
+		}
+	}
+
+	variables := buildByNameVariables(project, branch, build)
+	variables["count"] = count
+	if validationStamp != "" {
+		variables["validationStamp"] = validationStamp
+	}
+
+	if err := GraphQLCall(cfg, `
+		query ValidationRuns(
+			$project: String!,
+			$branch: String!,
+			$build: String!,
+			$validationStamp: String,
+			$count: Int
+		) {
+			`+buildByName+` {
+				`+buildKeyFields+`
+				validationRuns(validationStamp: $validationStamp, count: $count) {
+					`+validationRunFields+`
+				}
+			}
+		}
+	`, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if cfg.Disabled {
+		return nil, nil
+	}
+	node := findBuildByName(data.Builds, branch, build)
+	if node == nil {
+		return nil, fmt.Errorf("build %s %w in %s/%s", build, ErrNotFound, project, branch)
+	}
+
+	// Exact match on the validation stamp name
+	var runs []ValidationRun
+	for _, run := range node.ValidationRuns {
+		if validationStamp == "" || run.ValidationStamp.Name == validationStamp {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+// GetValidationRun returns a validation run using its ID
+func GetValidationRun(cfg *config.Config, id int) (*ValidationRun, error) {
+	var data struct {
+		ValidationRuns []ValidationRun
+	}
+	if err := GraphQLCall(cfg, `
+		query ValidationRun($id: Int!) {
+			validationRuns(id: $id) {
+				`+validationRunFields+`
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return nil, err
+	}
+
+	if cfg.Disabled {
+		return nil, nil
+	}
+	if len(data.ValidationRuns) == 0 {
+		return nil, fmt.Errorf("validation run %d %w", id, ErrNotFound)
+	}
+	return &data.ValidationRuns[0], nil
+}
+
+// GetLastValidationRun returns the most recent validation run of a build for a validation stamp,
+// or nil if the build has not been validated for this stamp.
+func GetLastValidationRun(
+	cfg *config.Config,
+	project string,
+	branch string,
+	build string,
+	validationStamp string,
+) (*ValidationRun, error) {
+	runs, err := GetValidationRuns(cfg, project, branch, build, validationStamp, 50)
+	if err != nil {
+		return nil, err
+	}
+	var last *ValidationRun
+	for index := range runs {
+		if last == nil || runs[index].RunOrder > last.RunOrder {
+			last = &runs[index]
+		}
+	}
+	return last, nil
+}
+
+// ChangeValidationRunStatus adds a new status to a validation run
+func ChangeValidationRunStatus(
+	cfg *config.Config,
+	id int,
+	status string,
+	description string,
+) error {
+
+	var payload struct {
+		ChangeValidationRunStatus struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+
+	if err := GraphQLCall(cfg, `
+		mutation ChangeValidationRunStatus(
+			$id: Int!,
+			$status: String!,
+			$description: String
+		) {
+			changeValidationRunStatus(input: {
+				validationRunId: $id,
+				validationRunStatus: $status,
+				description: $description
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id":          id,
+		"status":      status,
+		"description": description,
+	}, &payload); err != nil {
+		return err
+	}
+
+	return CheckDataErrors(payload.ChangeValidationRunStatus.Errors)
+}
+
+// GetLastValidationStatuses returns the status of the most recent validation run of a build,
+// indexed by validation stamp name. If the configuration is disabled, nil is returned.
+func GetLastValidationStatuses(
+	cfg *config.Config,
+	project string,
+	branch string,
+	build string,
+) (map[string]string, error) {
+	runs, err := GetValidationRuns(cfg, project, branch, build, "", 500)
+	if err != nil || runs == nil {
+		return nil, err
+	}
+	statuses := make(map[string]string)
+	for _, run := range lastValidationRuns(runs) {
+		statuses[run.ValidationStamp.Name] = run.LastStatus.StatusID.ID
+	}
+	return statuses, nil
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"fmt"
 	"text/template"
 
 	config "ontrack-cli/config"
@@ -84,3 +85,240 @@ func SetupValidationStamp(
 	// OK
 	return nil
 }
+
+// GetValidationStampNames returns the names of the validation stamps of a branch
+func GetValidationStampNames(
+	cfg *config.Config,
+	project string,
+	branch string,
+) ([]string, error) {
+
+	var data struct {
+		Branches []struct {
+			ValidationStamps []struct {
+				Name string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		query ValidationStampNames(
+			$project: String!,
+			$branch: String!
+		) {
+			branches(project: $project, name: $branch) {
+				validationStamps {
+					name
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project": project,
+		"branch":  branch,
+	}, &data); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, item := range data.Branches {
+		for _, validationStamp := range item.ValidationStamps {
+			names = append(names, validationStamp.Name)
+		}
+	}
+	return names, nil
+}
+
+// ValidationDataType is a type of validation data registered in Ontrack
+type ValidationDataType struct {
+	ID          string `json:"id" yaml:"id"`
+	DisplayName string `json:"displayName" yaml:"displayName"`
+	Feature     struct {
+		ID string `json:"id" yaml:"id"`
+	} `json:"feature" yaml:"feature"`
+}
+
+// GetValidationDataTypes returns the list of validation data types registered in Ontrack
+func GetValidationDataTypes(cfg *config.Config) ([]ValidationDataType, error) {
+	var data struct {
+		ValidationDataTypes []ValidationDataType
+	}
+	if err := GraphQLCall(cfg, `
+		query ValidationDataTypes {
+			validationDataTypes {
+				id
+				displayName
+				feature {
+					id
+				}
+			}
+		}
+	`, map[string]interface{}{}, &data); err != nil {
+		return nil, err
+	}
+	return data.ValidationDataTypes, nil
+}
+
+// ValidationStamp is a validation stamp of a branch, with its data type
+type ValidationStamp struct {
+	ID          int    `json:"id" yaml:"id"`
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Image       bool   `json:"image" yaml:"image"`
+	DataType    *struct {
+		Descriptor struct {
+			ID          string `json:"id" yaml:"id"`
+			DisplayName string `json:"displayName" yaml:"displayName"`
+		} `json:"descriptor" yaml:"descriptor"`
+		Config interface{} `json:"config" yaml:"config"`
+	} `json:"dataType,omitempty" yaml:"dataType,omitempty"`
+}
+
+// GetValidationStamps returns the validation stamps of a branch.
+// If name is not empty, only the validation stamp with this name is returned.
+func GetValidationStamps(
+	cfg *config.Config,
+	project string,
+	branch string,
+	name string,
+) ([]ValidationStamp, error) {
+
+	var data struct {
+		Branches []struct {
+			ValidationStamps []ValidationStamp
+		}
+	}
+
+	variables := map[string]interface{}{
+		"project": project,
+		"branch":  branch,
+	}
+	if name != "" {
+		variables["name"] = name
+	}
+
+	if err := GraphQLCall(cfg, `
+		query ValidationStamps(
+			$project: String!,
+			$branch: String!,
+			$name: String
+		) {
+			branches(project: $project, name: $branch) {
+				validationStamps(name: $name) {
+					id
+					name
+					description
+					image
+					dataType {
+						descriptor {
+							id
+							displayName
+						}
+						config
+					}
+				}
+			}
+		}
+	`, variables, &data); err != nil {
+		return nil, err
+	}
+
+	if cfg.Disabled {
+		return nil, nil
+	}
+	if len(data.Branches) == 0 {
+		return nil, fmt.Errorf("branch %s %w in %s", branch, ErrNotFound, project)
+	}
+	return data.Branches[0].ValidationStamps, nil
+}
+
+// GetValidationStamp returns a validation stamp using its name, or an error if not found.
+// If the configuration is disabled, nil is returned.
+func GetValidationStamp(
+	cfg *config.Config,
+	project string,
+	branch string,
+	name string,
+) (*ValidationStamp, error) {
+	stamps, err := GetValidationStamps(cfg, project, branch, name)
+	if err != nil || cfg.Disabled {
+		return nil, err
+	}
+	for index := range stamps {
+		if stamps[index].Name == name {
+			return &stamps[index], nil
+		}
+	}
+	return nil, fmt.Errorf("validation stamp %s %w in %s/%s", name, ErrNotFound, project, branch)
+}
+
+// UpdateValidationStamp changes the name and the description of a validation stamp
+func UpdateValidationStamp(
+	cfg *config.Config,
+	id int,
+	name string,
+	description string,
+) error {
+
+	var data struct {
+		UpdateValidationStampById struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation UpdateValidationStamp(
+			$id: Int!,
+			$name: String!,
+			$description: String
+		) {
+			updateValidationStampById(input: {
+				id: $id,
+				name: $name,
+				description: $description
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id":          id,
+		"name":        name,
+		"description": description,
+	}, &data); err != nil {
+		return err
+	}
+
+	return CheckDataErrors(data.UpdateValidationStampById.Errors)
+}
+
+// DeleteValidationStamp deletes a validation stamp and all its validation runs
+func DeleteValidationStamp(cfg *config.Config, id int) error {
+	var data struct {
+		DeleteValidationStampById struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+	if err := GraphQLCall(cfg, `
+		mutation DeleteValidationStamp($id: Int!) {
+			deleteValidationStampById(input: {id: $id}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"id": id,
+	}, &data); err != nil {
+		return err
+	}
+
+	return CheckDataErrors(data.DeleteValidationStampById.Errors)
+}
+
+// SetValidationStampImage uploads the image of a validation stamp
+func SetValidationStampImage(cfg *config.Config, id int, file string) error {
+	return UploadImage(cfg, fmt.Sprintf("/rest/structure/validationStamps/%d/image", id), file)
+}
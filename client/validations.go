@@ -77,3 +77,81 @@ func ValidateWithTests(
 	// OK
 	return nil
 }
+
+func ValidateWithCHML(
+	cfg *config.Config,
+	project string,
+	branch string,
+	build string,
+	validation string,
+	description string,
+	runInfo *RunInfo,
+	critical int,
+	high int,
+	medium int,
+	low int,
+) error {
+
+	// Mutation payload
+	var payload struct {
+		ValidateBuildWithCHML struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+
+	// Runs the mutation
+	if err := GraphQLCall(cfg, `
+			mutation ValidateBuildWithCHML(
+				$project: String!,
+				$branch: String!,
+				$build: String!,
+				$validationStamp: String!,
+				$description: String!,
+				$runInfo: RunInfoInput,
+				$critical: Int!,
+				$high: Int!,
+				$medium: Int!,
+				$low: Int!
+			) {
+				validateBuildWithCHML(input: {
+					project: $project,
+					branch: $branch,
+					build: $build,
+					validation: $validationStamp,
+					description: $description,
+					runInfo: $runInfo,
+					critical: $critical,
+					high: $high,
+					medium: $medium,
+					low: $low
+				}) {
+					errors {
+						message
+					}
+				}
+			}
+		`, map[string]interface{}{
+		"project":         project,
+		"branch":          branch,
+		"build":           build,
+		"validationStamp": validation,
+		"description":     description,
+		"runInfo":         runInfo,
+		"critical":        critical,
+		"high":            high,
+		"medium":          medium,
+		"low":             low,
+	}, &payload); err != nil {
+		return err
+	}
+
+	// Checks for errors
+	if err := CheckDataErrors(payload.ValidateBuildWithCHML.Errors); err != nil {
+		return err
+	}
+
+	// OK
+	return nil
+}
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
+	"ontrack-cli/cmd/manifest"
+	"ontrack-cli/cmd/promotions"
+	config "ontrack-cli/config"
+)
+
+// Results of the resources of a manifest
+const (
+	ApplyResultOK        = "ok"
+	ApplyResultUnchanged = "unchanged"
+	ApplyResultSkipped   = "skipped"
+	ApplyResultFailed    = "failed"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Applies a manifest describing projects, branches, validation stamps and promotion levels",
+	Long: `Applies a manifest describing projects, branches, validation stamps and promotion levels.
+
+    ontrack-cli apply -f ontrack.yaml
+
+The manifest, in YAML or JSON, has the following structure (example):
+
+    projects:
+      - name: my-project
+        properties:
+          github:
+            configuration: GitHub
+            repository: my-org/my-project
+            indexation: 30
+            issueService: self
+          # bitbucketCloud: same fields as github
+          autoValidationStamps:
+            autoCreate: true
+            autoCreateIfNotPredefined: false
+          autoPromotionLevels:
+            autoCreate: true
+          generic:
+            net.nemerosa.ontrack.extension.general.MetaInfoPropertyType: '{items: [{name: "owner", value: "team-a"}]}'
+        branches:
+          - name: main
+            properties:
+              git:
+                branch: main
+            validations:
+              - name: unit-tests
+                tests:
+                  warningIfSkipped: false
+            promotions:
+              - name: BRONZE
+                validations:
+                  - unit-tests
+
+The validations and promotions of a branch have the same format as in the file used by 'ontrack-cli pl auto'.
+The name of a branch can be the name of the Git branch, in which case it is adapted to the Ontrack naming
+conventions and used by default for the 'git' property.
+
+The manifest is applied idempotently: only the missing or different resources are created or updated, and
+nothing is ever deleted. The changes can be previewed, without applying them, using:
+
+    ontrack-cli apply -f ontrack.yaml --dry-run
+
+The result of each resource is printed at the end, and can be printed as JSON or YAML using '--output'.
+When a project or a branch cannot be created, its resources are skipped.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		// Reading & checking the manifest
+		m, err := manifest.Read(file)
+		if err != nil {
+			// The usage is not relevant for an invalid file
+			cmd.SilenceUsage = true
+			return err
+		}
+		normalizeManifestBranches(m)
+
+		// Configuration
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Plan against the current state
+		changes, err := manifest.Plan(m, &clientState{cfg: cfg})
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			manifest.PrintPlan(os.Stdout, changes)
+			return nil
+		}
+
+		// Applying the changes, in order
+		var results []applyResult
+		failed := 0
+		var failedProject, failedBranch string
+		for _, change := range changes {
+			result := applyResult{Change: change, Result: ApplyResultOK}
+			switch {
+			case change.Project == failedProject || (change.Branch != "" && change.Project+"/"+change.Branch == failedBranch):
+				result.Result = ApplyResultSkipped
+			case change.Action == promotions.ActionNone:
+				result.Result = ApplyResultUnchanged
+			default:
+				if err := applyChange(cfg, m, change); err != nil {
+					failed++
+					result.Result = ApplyResultFailed
+					result.Error = err.Error()
+					if change.Resource == manifest.ResourceProject {
+						failedProject = change.Project
+					} else if change.Resource == manifest.ResourceBranch {
+						failedBranch = change.Project + "/" + change.Branch
+					}
+				}
+			}
+			results = append(results, result)
+		}
+
+		if err := PrintOutput(cmd, results, func(w io.Writer) error {
+			fmt.Fprintln(w, "RESOURCE\tPROJECT\tBRANCH\tNAME\tACTION\tRESULT")
+			for _, result := range results {
+				text := result.Result
+				if result.Error != "" {
+					text += ": " + result.Error
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					result.Resource,
+					result.Project,
+					result.Branch,
+					result.Name,
+					result.Action,
+					text,
+				)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d resource(s) could not be applied", failed)
+		}
+
+		// OK
+		return nil
+	},
+}
+
+// applyResult is the result of the application of a change
+type applyResult struct {
+	manifest.Change `yaml:",inline"`
+	Result          string `json:"result" yaml:"result"`
+	Error           string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// normalizeManifestBranches adapts the names of the branches to the Ontrack naming conventions,
+// the original names being used by default for their Git property
+func normalizeManifestBranches(m *manifest.Manifest) {
+	for i := range m.Projects {
+		for j := range m.Projects[i].Branches {
+			branch := &m.Projects[i].Branches[j]
+			if branch.Properties != nil && branch.Properties.Git != nil && branch.Properties.Git.Branch == "" {
+				branch.Properties.Git.Branch = branch.Name
+			}
+			branch.Name = NormalizeBranchName(branch.Name)
+		}
+	}
+}
+
+// applyChange creates or updates a resource of the manifest
+func applyChange(cfg *config.Config, m *manifest.Manifest, change manifest.Change) error {
+	project := findManifestProject(m, change.Project)
+	var branch *manifest.Branch
+	for index := range project.Branches {
+		if project.Branches[index].Name == change.Branch {
+			branch = &project.Branches[index]
+		}
+	}
+
+	switch change.Resource {
+	case manifest.ResourceProject:
+		return client.CreateProjectOrGet(cfg, change.Project)
+	case manifest.ResourceBranch:
+		return client.CreateBranchOrGet(cfg, change.Project, change.Branch)
+	case manifest.ResourceProperty:
+		if branch != nil {
+			return applyBranchProperty(cfg, change, branch.Properties)
+		}
+		return applyProjectProperty(cfg, change, project.Properties)
+	case manifest.ResourceValidation:
+		for _, validation := range branch.Validations {
+			if validation.Name == change.Name {
+				return setupAutoValidation(cfg, change.Project, change.Branch, validation)
+			}
+		}
+		// Validation only used by the promotions
+		return setupAutoValidation(cfg, change.Project, change.Branch, promotions.Validation{Name: change.Name})
+	case manifest.ResourcePromotion:
+		for _, promotion := range branch.Promotions {
+			if promotion.Name == change.Name {
+				return setupAutoPromotion(cfg, change.Project, change.Branch, promotion)
+			}
+		}
+	}
+	return fmt.Errorf("unknown %s %s", change.Resource, change.Name)
+}
+
+func findManifestProject(m *manifest.Manifest, name string) *manifest.Project {
+	for index := range m.Projects {
+		if m.Projects[index].Name == name {
+			return &m.Projects[index]
+		}
+	}
+	return &manifest.Project{Name: name}
+}
+
+func applyProjectProperty(cfg *config.Config, change manifest.Change, properties *manifest.ProjectProperties) error {
+	switch change.Name {
+	case manifest.PropertyGitHub:
+		github := properties.GitHub
+		return client.SetProjectGitHubProperty(cfg, change.Project, github.Configuration, github.Repository, github.Indexation, github.IssueService)
+	case manifest.PropertyBitbucketCloud:
+		bitbucket := properties.BitbucketCloud
+		return client.SetProjectBitbucketCloudProperty(cfg, change.Project, bitbucket.Configuration, bitbucket.Repository, bitbucket.Indexation, bitbucket.IssueService)
+	case manifest.PropertyAutoValidationStamps:
+		return client.SetProjectAutoValidationStampProperty(cfg, change.Project, properties.AutoValidationStamps.IsAutoCreate(), properties.AutoValidationStamps.AutoCreateIfNotPredefined)
+	case manifest.PropertyAutoPromotionLevels:
+		return client.SetProjectAutoPromotionLevelProperty(cfg, change.Project, properties.AutoPromotionLevels.IsAutoCreate())
+	default:
+		return applyGenericProperty("project", map[string]string{
+			"project": change.Project,
+		}, change.Name, properties.Generic[change.Name])
+	}
+}
+
+func applyBranchProperty(cfg *config.Config, change manifest.Change, properties *manifest.BranchProperties) error {
+	switch change.Name {
+	case manifest.PropertyGit:
+		return client.SetBranchGitProperty(cfg, change.Project, change.Branch, properties.Git.Branch)
+	default:
+		return applyGenericProperty("branch", map[string]string{
+			"project": change.Project,
+			"branch":  change.Branch,
+		}, change.Name, properties.Generic[change.Name])
+	}
+}
+
+// applyGenericProperty sets a property given as JSON or as a GraphQL literal
+func applyGenericProperty(entityType string, entityNames map[string]string, propertyType string, text string) error {
+	value, err := datatypes.ParseValue(text)
+	if err != nil {
+		return err
+	}
+	return SetProperty(entityType, entityNames, propertyType, datatypes.GraphQLLiteral(value))
+}
+
+// clientState reads the current state from Ontrack
+type clientState struct {
+	cfg *config.Config
+}
+
+func (s *clientState) Project(name string) (*client.Project, error) {
+	return client.GetProject(s.cfg, name)
+}
+
+func (s *clientState) ValidationStamps(project string, branch string) ([]client.ValidationStamp, error) {
+	return client.GetValidationStamps(s.cfg, project, branch, "")
+}
+
+func (s *clientState) PromotionLevels(project string, branch string) ([]client.PromotionLevel, error) {
+	levels, err := client.GetPromotionLevels(s.cfg, project, branch)
+	if err != nil || levels == nil {
+		return nil, err
+	}
+	return levels.PromotionLevels, nil
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringP("file", "f", "", "Path to the manifest, in YAML or JSON")
+	applyCmd.Flags().Bool("dry-run", false, "Prints the changes to apply, without applying them")
+	InitOutputCommandFlags(applyCmd)
+
+	applyCmd.MarkFlagRequired("file")
+}
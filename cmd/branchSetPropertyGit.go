@@ -65,43 +65,7 @@ As of now, this also sets the "GitCommitPropertyLink" property by default (build
 			return err
 		}
 
-		var data struct {
-			SetBranchGitConfigProperty struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-		if err := client.GraphQLCall(cfg, `
-			mutation SetBranchGitConfigProperty(
-				$project: String!,
-				$branch: String!,
-				$gitBranch: String!
-			) {
-				setBranchGitConfigProperty(input: {
-					project: $project,
-					branch: $branch,
-					gitBranch: $gitBranch
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":   project,
-			"branch":    branch,
-			"gitBranch": gitBranch,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetBranchGitConfigProperty.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return client.SetBranchGitProperty(cfg, project, branch, gitBranch)
 	},
 }
 
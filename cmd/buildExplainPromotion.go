@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var buildExplainPromotionCmd = &cobra.Command{
+	Use:   "explain-promotion",
+	Short: "Explains why a build is or is not auto promoted",
+	Long: `Checks the auto promotion criteria of a promotion level against the latest runs of a build.
+
+	ontrack-cli build explain-promotion -p PROJECT -b BRANCH -n BUILD -l PROMOTION
+
+Each criterion of the auto promotion is listed as satisfied ([x]) or missing ([ ]):
+
+* the validation stamps listed for the promotion level, whose last run must be PASSED
+* the validation stamps of the branch matching the include expression and not the exclude one
+* the promotion levels the promotion level depends on
+
+Without '-l', all the promotion levels of the branch having an auto promotion are explained.
+
+The command exits with code 4 if the build is not promoted and cannot be auto promoted.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		promotion, err := cmd.Flags().GetString("promotion")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Build reference
+		build, err = ResolveBuild(cfg, project, branch, build)
+		if err != nil {
+			return err
+		}
+
+		// Promotion levels & their auto promotion
+		levels, err := client.GetPromotionLevels(cfg, project, branch)
+		if err != nil || levels == nil {
+			return err
+		}
+		var explained []client.PromotionLevel
+		for _, level := range levels.PromotionLevels {
+			if promotion != "" && level.Name == promotion {
+				explained = append(explained, level)
+			} else if promotion == "" && level.AutoPromotion != nil {
+				explained = append(explained, level)
+			}
+		}
+		if promotion != "" && len(explained) == 0 {
+			return fmt.Errorf("promotion level %s not found in %s/%s", promotion, project, branch)
+		}
+		if len(explained) == 0 {
+			fmt.Printf("No promotion level of %s/%s has an auto promotion.\n", project, branch)
+			return nil
+		}
+
+		// State of the build
+		state, err := getBuildPromotionState(cfg, project, branch, build)
+		if err != nil || state == nil {
+			return err
+		}
+		validationStamps, err := client.GetValidationStampNames(cfg, project, branch)
+		if err != nil {
+			return err
+		}
+
+		// Checklist per promotion level
+		var unreachable []string
+		for _, level := range explained {
+			promoted := state.promotionCriterion(level.Name).Satisfied
+			if level.AutoPromotion == nil {
+				if promoted {
+					fmt.Printf("%s: build %s is promoted, the promotion level has no auto promotion\n", level.Name, build)
+				} else {
+					fmt.Printf("%s: not reachable, the promotion level has no auto promotion\n", level.Name)
+					unreachable = append(unreachable, level.Name)
+				}
+				continue
+			}
+			criteria, err := state.autoPromotionCriteria(level.AutoPromotion, validationStamps)
+			if err != nil {
+				return err
+			}
+			missing := 0
+			if promoted {
+				fmt.Printf("%s: build %s is promoted\n", level.Name, build)
+				printPromotionCriteria(os.Stdout, criteria)
+			} else {
+				fmt.Printf("%s:\n", level.Name)
+				missing = printPromotionCriteria(os.Stdout, criteria)
+			}
+			if missing > 0 {
+				fmt.Printf("  => not reachable, %d missing criteria\n", missing)
+				unreachable = append(unreachable, level.Name)
+			} else if !promoted {
+				fmt.Println("  => all criteria are met")
+			}
+		}
+
+		if len(unreachable) > 0 {
+			// The usage is not relevant for an unreachable promotion
+			cmd.SilenceUsage = true
+			return NewExitError(ExitCodeCriteriaNotMet, "Build %s cannot be promoted to %s", build, strings.Join(unreachable, ", "))
+		}
+
+		// OK
+		return nil
+	},
+}
+
+func init() {
+	buildCmd.AddCommand(buildExplainPromotionCmd)
+
+	buildExplainPromotionCmd.Flags().StringP("project", "p", "", "Name of the project")
+	buildExplainPromotionCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+	buildExplainPromotionCmd.Flags().StringP("build", "n", "", buildRefUsage)
+	buildExplainPromotionCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level (all the auto promoted levels by default)")
+
+	buildExplainPromotionCmd.MarkFlagRequired("project")
+	buildExplainPromotionCmd.MarkFlagRequired("branch")
+	buildExplainPromotionCmd.MarkFlagRequired("build")
+}
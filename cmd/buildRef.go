@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/buildref"
+	config "ontrack-cli/config"
+)
+
+// Help of the `build` flag of the commands accepting a build reference
+const buildRefUsage = "Name of the build, or reference: @latest, @latest:<promotion>, @commit:<sha>, @release:<release>, #<id>"
+
+// ResolveBuild returns the name of the build designated by a build name or reference
+// in a branch. The reference is returned as is if the configuration is disabled.
+func ResolveBuild(cfg *config.Config, project string, branch string, text string) (string, error) {
+	ref, err := buildref.Parse(text)
+	if err != nil {
+		return "", err
+	}
+	if ref.Kind == buildref.KindName || cfg.Disabled {
+		return text, nil
+	}
+
+	// Using the ID
+	if ref.Kind == buildref.KindID {
+		build, err := client.GetBuildByID(cfg, ref.ID)
+		if err != nil {
+			return "", err
+		}
+		if build.Project != project || build.Branch != branch {
+			return "", fmt.Errorf("build %s is %s/%s/%s, not in %s/%s", text, build.Project, build.Branch, build.Name, project, branch)
+		}
+		return build.Name, nil
+	}
+
+	// Using the branch search
+	var data buildList
+	if err := branchSearch(cfg, project, branch, ref.Form(), &data); err != nil {
+		return "", err
+	}
+	var names []string
+	for _, build := range data.Builds {
+		names = append(names, build.Name)
+	}
+	return ref.Select(names, project, branch)
+}
+
+// resolveBuildFlag replaces a build reference in the `build` flag by the name of the build,
+// for the subcommands reading the flag directly
+func resolveBuildFlag(cmd *cobra.Command) error {
+	build, err := cmd.Flags().GetString("build")
+	if err != nil || build == "" {
+		return err
+	}
+	project, err := cmd.Flags().GetString("project")
+	if err != nil {
+		return err
+	}
+	branch, err := cmd.Flags().GetString("branch")
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return err
+	}
+	name, err := ResolveBuild(cfg, project, NormalizeBranchName(branch), build)
+	if err != nil {
+		return err
+	}
+	return cmd.Flags().Set("build", name)
+}
@@ -22,13 +22,35 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"encoding/csv"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
 	"ontrack-cli/client"
 	config "ontrack-cli/config"
 
 	"github.com/spf13/cobra"
 )
 
+// Fields which can be displayed for a build
+const (
+	BuildFieldID         = "id"
+	BuildFieldName       = "name"
+	BuildFieldBranch     = "branch"
+	BuildFieldCreation   = "creation"
+	BuildFieldPromotions = "promotions"
+)
+
+var buildFields = []string{BuildFieldID, BuildFieldName, BuildFieldBranch, BuildFieldCreation, BuildFieldPromotions}
+
+// Output format of the build search, in addition to the common ones
+const OutputCSV = "csv"
+
 // buildSearchCmd represents the buildSearch command
 var buildSearchCmd = &cobra.Command{
 	Use:   "search",
@@ -48,9 +70,23 @@ to look for a build using its commit:
 
     ontrack-cli build search --project PROJECT --branch BRANCH --commit commit
 
+or for the builds of a branch created in a given period and validated by a given stamp:
+
+    ontrack-cli build search --project PROJECT --branch BRANCH --after 2024-01-01 --before 2024-02-01 --with-validation unit:PASSED
+
+Some criteria are only available on a branch: '--after', '--before', '--since-promotion' and the status of
+'--with-validation'. The '--name' criterion is only available on a project, and is ignored with a warning on a branch. Only one property criterion can be used among '--commit', '--release' and '--property'.
+
 By default, only the build names are printed, one per line.
 
-You can change the display options using additional flags - see 'ontrack-cli build search --help' to get their list.`,
+You can change the display options using additional flags - see 'ontrack-cli build search --help' to get their list.
+Using '--output table|json|yaml|csv', the fields selected by '--fields' are printed instead:
+
+    ontrack-cli build search --project PROJECT --branch BRANCH --output csv --fields id,name,creation
+
+The builds are returned the most recent first, unless sorted using '--sort' (and '--desc'). The sorting
+applies only to the builds returned by Ontrack, after the '--count' limit: for example, '--count 10 --sort name'
+sorts the 10 most recent builds by name.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		project, err := cmd.Flags().GetString("project")
@@ -64,28 +100,76 @@ You can change the display options using additional flags - see 'ontrack-cli bui
 		}
 		branch = NormalizeBranchName(branch)
 
+		// Search form
+		form, err := buildSearchForm(cmd, branch != "")
+		if err != nil {
+			return err
+		}
+
+		// Gets the configuration
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
 		// Project vs. branch search
+		var data buildList
 		if branch == "" {
-			return projectSearch(cmd, project)
+			err = projectSearch(cfg, project, form, &data)
 		} else {
-			return branchSearch(cmd, project, branch)
+			err = branchSearch(cfg, project, branch, form, &data)
+		}
+		if err != nil {
+			return err
 		}
+
+		if err := sortBuilds(cmd, data.Builds); err != nil {
+			return err
+		}
+
+		// Displaying the data
+		return displayBuilds(cmd, &data)
 	},
 }
 
 type buildList struct {
-	Builds []struct {
-		Id     int
-		Name   string
-		Branch struct {
+	Builds []buildItem
+}
+
+type buildItem struct {
+	Id     int
+	Name   string
+	Branch struct {
+		Name string
+	}
+	Creation      client.Signature
+	PromotionRuns []struct {
+		PromotionLevel struct {
 			Name string
 		}
 	}
 }
 
-func projectSearch(cmd *cobra.Command, project string) error {
-	// Query
-	query := `
+// GraphQL fields for a build in the search results
+const buildItemFields = `
+	id
+	name
+	branch {
+		name
+	}
+	creation {
+		user
+		time
+	}
+	promotionRuns {
+		promotionLevel {
+			name
+		}
+	}
+`
+
+func projectSearch(cfg *config.Config, project string, form map[string]interface{}, data *buildList) error {
+	return client.GraphQLCall(cfg, `
 		query BuildProjectSearch(
 			$project: String!,
 			$buildProjectFilter: BuildSearchForm!
@@ -94,119 +178,236 @@ func projectSearch(cmd *cobra.Command, project string) error {
 				project: $project,
 				buildProjectFilter: $buildProjectFilter
 			) {
-				id
-				name
-				branch {
-					name
-				}
+				`+buildItemFields+`
 			}
 		}
-	`
+	`, map[string]interface{}{
+		"project":            project,
+		"buildProjectFilter": form,
+	}, data)
+}
 
-	// Search form
+func branchSearch(cfg *config.Config, project string, branch string, form map[string]interface{}, data *buildList) error {
+	return client.GraphQLCall(cfg, `
+		query BuildBranchSearch(
+			$project: String!,
+			$branch: String!,
+			$buildBranchFilter: StandardBuildFilter!
+		) {
+			builds(
+				project: $project,
+				branch: $branch,
+				buildBranchFilter: $buildBranchFilter
+			) {
+				`+buildItemFields+`
+			}
+		}
+	`, map[string]interface{}{
+		"project":           project,
+		"branch":            branch,
+		"buildBranchFilter": form,
+	}, data)
+}
+
+// buildSearchForm builds the search form from the criteria flags, either for a branch
+// (StandardBuildFilter) or for a project (BuildSearchForm)
+func buildSearchForm(cmd *cobra.Command, onBranch bool) (map[string]interface{}, error) {
 	form := make(map[string]interface{})
-	if err := fillFormWithProperty(cmd, &form, "property", "propertyValue"); err != nil {
-		return err
+
+	// Sets a field, using its name in the branch or in the project form, an empty name
+	// meaning that the criterion is not supported
+	set := func(flag string, branchField string, projectField string, value interface{}) error {
+		field := projectField
+		if onBranch {
+			field = branchField
+		}
+		if field == "" {
+			if onBranch {
+				return fmt.Errorf("--%s can only be used when searching a project", flag)
+			}
+			return fmt.Errorf("--%s can only be used when searching a branch", flag)
+		}
+		form[field] = value
+		return nil
 	}
-	if err := fillFormWithCount(cmd, &form, "maximumCount"); err != nil {
-		return err
+
+	// Simple criteria
+	criteria := []struct {
+		flag         string
+		branchField  string
+		projectField string
+	}{
+		{"with-promotion", "withPromotionLevel", "promotionName"},
+		{"since-promotion", "sincePromotionLevel", ""},
+		{"linked-from", "linkedFrom", "linkedFrom"},
+		{"linked-to", "linkedTo", "linkedTo"},
+	}
+	for _, criterion := range criteria {
+		value, err := cmd.Flags().GetString(criterion.flag)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			if err := set(criterion.flag, criterion.branchField, criterion.projectField, value); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	if err := fillFormWithWithPromotion(cmd, &form, "promotionName"); err != nil {
-		return err
+	// Count
+	count, err := cmd.Flags().GetInt("count")
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		set("count", "count", "maximumCount", count)
 	}
 
+	// Name, only for a project, ignored on a branch as it has always been
 	name, err := cmd.Flags().GetString("name")
 	if err != nil {
-		return err
+		return nil, err
+	} else if name != "" && onBranch {
+		fmt.Fprintln(os.Stderr, "Warning: --name is ignored when searching a branch")
 	} else if name != "" {
-		form["buildName"] = name
+		set("name", "", "buildName", name)
 		nameExact, err := cmd.Flags().GetBool("name-exact")
 		if err != nil {
-			return err
+			return nil, err
 		} else if nameExact {
 			form["buildExactMatch"] = true
 		}
 	}
 
-	// Gets the configuration
-	cfg, err := config.GetSelectedConfiguration()
-	if err != nil {
-		return err
+	// Dates
+	for _, date := range []struct {
+		flag  string
+		field string
+	}{
+		{"after", "afterDate"},
+		{"before", "beforeDate"},
+	} {
+		value, err := cmd.Flags().GetString(date.flag)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			if _, err := time.Parse("2006-01-02", value); err != nil {
+				return nil, fmt.Errorf("--%s must be a date like 2006-01-02: %s", date.flag, value)
+			}
+			if err := set(date.flag, date.field, "", value); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	// Result data
-	var data buildList
-
-	// Call
-	if err := client.GraphQLCall(cfg, query, map[string]interface{}{
-		"project":            project,
-		"buildProjectFilter": form,
-	}, &data); err != nil {
-		return err
+	// Validation & its status
+	validation, err := cmd.Flags().GetString("with-validation")
+	if err != nil {
+		return nil, err
 	}
-
-	// Displaying the data
-	return displayBuilds(cmd, &data)
-}
-
-func branchSearch(cmd *cobra.Command, project string, branch string) error {
-	// Query
-	query := `
-		query BuildBranchSearch(
-			$project: String!,
-			$branch: String!,
-			$buildBranchFilter: StandardBuildFilter!
-		) {
-			builds(
-				project: $project,
-				branch: $branch,
-				buildBranchFilter: $buildBranchFilter
-			) {
-				name
-				branch {
-					name
-				}
+	if validation != "" {
+		stamp, status, hasStatus := strings.Cut(validation, ":")
+		if err := set("with-validation", "withValidationStamp", "validationStampName", stamp); err != nil {
+			return nil, err
+		}
+		if hasStatus {
+			if err := set("with-validation", "withValidationStampStatus", "", status); err != nil {
+				return nil, fmt.Errorf("the status of --with-validation can only be used when searching a branch")
 			}
 		}
-	`
+	}
 
-	// Search form
-	form := make(map[string]interface{})
-	if err := fillFormWithProperty(cmd, &form, "withProperty", "withPropertyValue"); err != nil {
-		return err
+	// Property, only one being supported by the forms
+	var propertyFlags []string
+	property := func(flag string, propertyType string, value string) error {
+		propertyFlags = append(propertyFlags, "--"+flag)
+		if len(propertyFlags) > 1 {
+			return fmt.Errorf("only one property criterion can be used: %s", strings.Join(propertyFlags, ", "))
+		}
+		set(flag, "withProperty", "property", propertyType)
+		set(flag, "withPropertyValue", "propertyValue", value)
+		return nil
 	}
-	if err := fillFormWithCount(cmd, &form, "count"); err != nil {
-		return err
+	for _, flag := range []struct {
+		name         string
+		propertyType string
+	}{
+		{"commit", client.GitCommitPropertyType},
+		{"release", client.ReleasePropertyType},
+	} {
+		value, err := cmd.Flags().GetString(flag.name)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			if err := property(flag.name, flag.propertyType, value); err != nil {
+				return nil, err
+			}
+		}
 	}
-
-	if err := fillFormWithWithPromotion(cmd, &form, "withPromotionLevel"); err != nil {
-		return err
+	generic, err := cmd.Flags().GetString("property")
+	if err != nil {
+		return nil, err
 	}
+	if generic != "" {
+		propertyType, value, _ := strings.Cut(generic, "=")
+		if propertyType == "" {
+			return nil, fmt.Errorf("--property must be like FQCN=value: %s", generic)
+		}
+		if err := property("property", propertyType, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return form, nil
+}
 
-	// Gets the configuration
-	cfg, err := config.GetSelectedConfiguration()
+// sortBuilds sorts the builds according to the `sort` and `desc` flags
+func sortBuilds(cmd *cobra.Command, builds []buildItem) error {
+	field, err := cmd.Flags().GetString("sort")
 	if err != nil {
 		return err
 	}
-
-	// Result data
-	var data buildList
-
-	// Call
-	if err := client.GraphQLCall(cfg, query, map[string]interface{}{
-		"project":           project,
-		"branch":            branch,
-		"buildBranchFilter": form,
-	}, &data); err != nil {
+	desc, err := cmd.Flags().GetBool("desc")
+	if err != nil {
 		return err
 	}
+	if field == "" {
+		return nil
+	}
 
-	// Displaying the data
-	return displayBuilds(cmd, &data)
+	var less func(a, b buildItem) bool
+	switch field {
+	case BuildFieldID:
+		less = func(a, b buildItem) bool { return a.Id < b.Id }
+	case BuildFieldName:
+		less = func(a, b buildItem) bool { return a.Name < b.Name }
+	case BuildFieldBranch:
+		less = func(a, b buildItem) bool { return a.Branch.Name < b.Branch.Name }
+	case BuildFieldCreation:
+		less = func(a, b buildItem) bool { return a.Creation.Time < b.Creation.Time }
+	default:
+		return fmt.Errorf("cannot sort on %q, must be one of %s, %s, %s or %s", field, BuildFieldID, BuildFieldName, BuildFieldBranch, BuildFieldCreation)
+	}
+	sort.SliceStable(builds, func(i, j int) bool {
+		if desc {
+			return less(builds[j], builds[i])
+		}
+		return less(builds[i], builds[j])
+	})
+	return nil
 }
 
 func displayBuilds(cmd *cobra.Command, data *buildList) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output != "" {
+		return displayBuildFields(cmd, output, data)
+	}
+
 	displayBranch, err := cmd.Flags().GetBool("display-branch")
 	if err != nil {
 		return err
@@ -230,48 +431,85 @@ func displayBuilds(cmd *cobra.Command, data *buildList) error {
 	return nil
 }
 
-func fillFormWithCount(cmd *cobra.Command, form *map[string]interface{}, countField string) error {
-	count, err := cmd.Flags().GetInt("count")
+// displayBuildFields prints the fields selected by the `fields` flag
+func displayBuildFields(cmd *cobra.Command, output string, data *buildList) error {
+	fields, err := cmd.Flags().GetStringSlice("fields")
 	if err != nil {
 		return err
 	}
-
-	if count > 0 {
-		(*form)[countField] = count
+	for _, field := range fields {
+		if buildFieldValue(buildItem{}, field) == nil {
+			return fmt.Errorf("unknown field %q, must be one of %s", field, strings.Join(buildFields, ", "))
+		}
 	}
 
-	return nil
-}
-
-func fillFormWithProperty(cmd *cobra.Command, form *map[string]interface{}, propertyTypeField string, propertyValueField string) error {
-	commit, err := cmd.Flags().GetString("commit")
-	if err != nil {
-		return err
+	// Selected fields only
+	items := make([]map[string]interface{}, 0, len(data.Builds))
+	for _, build := range data.Builds {
+		item := make(map[string]interface{})
+		for _, field := range fields {
+			item[field] = buildFieldValue(build, field)
+		}
+		items = append(items, item)
 	}
 
-	if commit != "" {
-		(*form)[propertyTypeField] = "net.nemerosa.ontrack.extension.git.property.GitCommitPropertyType"
-		(*form)[propertyValueField] = commit
+	// Fields as text
+	text := func(value interface{}) string {
+		if promotions, ok := value.([]string); ok {
+			return strings.Join(promotions, ",")
+		}
+		return fmt.Sprintf("%v", value)
 	}
 
-	return nil
-}
+	if output == OutputCSV {
+		w := csv.NewWriter(os.Stdout)
+		w.Write(fields)
+		for _, item := range items {
+			var record []string
+			for _, field := range fields {
+				record = append(record, text(item[field]))
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		return w.Error()
+	}
 
-func fillFormWithWithPromotion(cmd *cobra.Command, form *map[string]interface{}, fieldName string) error {
-	return fillForm(cmd, form, "with-promotion", fieldName)
+	return PrintOutput(cmd, items, func(w io.Writer) error {
+		fmt.Fprintln(w, strings.ToUpper(strings.Join(fields, "\t")))
+		for _, item := range items {
+			var values []string
+			for _, field := range fields {
+				values = append(values, text(item[field]))
+			}
+			fmt.Fprintln(w, strings.Join(values, "\t"))
+		}
+		return nil
+	})
 }
 
-func fillForm(cmd *cobra.Command, form *map[string]interface{}, argName string, fieldName string) error {
-	value, err := cmd.Flags().GetString(argName)
-	if err != nil {
-		return err
-	}
-
-	if value != "" {
-		(*form)[fieldName] = value
+// buildFieldValue returns the value of a field, nil if the field is unknown
+func buildFieldValue(build buildItem, field string) interface{} {
+	switch field {
+	case BuildFieldID:
+		return build.Id
+	case BuildFieldName:
+		return build.Name
+	case BuildFieldBranch:
+		return build.Branch.Name
+	case BuildFieldCreation:
+		return build.Creation.Time
+	case BuildFieldPromotions:
+		promotions := []string{}
+		for _, run := range build.PromotionRuns {
+			if !slices.Contains(promotions, run.PromotionLevel.Name) {
+				promotions = append(promotions, run.PromotionLevel.Name)
+			}
+		}
+		return promotions
+	default:
+		return nil
 	}
-
-	return nil
 }
 
 func init() {
@@ -293,13 +531,27 @@ func init() {
 	// Criteria
 	buildSearchCmd.Flags().Int("count", 10, "Number of builds to return")
 	buildSearchCmd.Flags().String("with-promotion", "", "Builds must have this promotion")
-	buildSearchCmd.Flags().String("name", "", "Builds must have this name or match this regular expression")
+	buildSearchCmd.Flags().String("since-promotion", "", "Builds since the last one having this promotion. Used only for branch-based searches.")
+	buildSearchCmd.Flags().String("with-validation", "", "Builds must have this validation, as VS[:STATUS]. The status is used only for branch-based searches.")
+	buildSearchCmd.Flags().String("name", "", "Builds must have this name or match this regular expression. Used only for project-based searches, ignored with a warning otherwise.")
 	buildSearchCmd.Flags().Bool("name-exact", true, "If present together with the `name` flag, requires an exact match.")
+	buildSearchCmd.Flags().String("after", "", "Builds created on or after this date (YYYY-MM-DD). Used only for branch-based searches.")
+	buildSearchCmd.Flags().String("before", "", "Builds created on or before this date (YYYY-MM-DD). Used only for branch-based searches.")
+	buildSearchCmd.Flags().String("linked-from", "", "Builds used by this build, as PROJECT[:BUILD]")
+	buildSearchCmd.Flags().String("linked-to", "", "Builds using this build, as PROJECT[:BUILD]")
 
 	// Property criteria
 	buildSearchCmd.Flags().String("commit", "", "Commit for the build")
+	buildSearchCmd.Flags().String("release", "", "Release for the build")
+	buildSearchCmd.Flags().String("property", "", "Property of the build, as FQCN=value")
+
+	// Sorting
+	buildSearchCmd.Flags().String("sort", "", "Sorts the returned builds on id, name, branch or creation, after the --count limit")
+	buildSearchCmd.Flags().Bool("desc", false, "Sorts the builds in descending order")
 
 	// Display criteria
 	buildSearchCmd.Flags().Bool("display-branch", false, "Displays branch information: <branch name>/<build name>. Used only for project-based searches.")
 	buildSearchCmd.Flags().Bool("display-id", false, "Displays the build ID instead of its name.")
+	buildSearchCmd.Flags().String("output", "", "Output format of the fields: table, json, yaml or csv. Only the build names are printed by default.")
+	buildSearchCmd.Flags().StringSlice("fields", buildFields, "Fields to print when --output is set: id, name, branch, creation, promotions")
 }
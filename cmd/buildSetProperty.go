@@ -39,8 +39,13 @@ Some specific commands are also available for the most common property types. Th
 same update than the one just above:
 
 	ontrack-cli build set-property --project PROJECT --branch BRANCH --build BUILD git --commit bae524d43cf454386408cae4c174b12b11de90d0
+
+The build can also be given by reference, like '--build @latest' - see 'ontrack-cli build show --help'.
 `,
 	// Run: func(cmd *cobra.Command, args []string) {},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return resolveBuildFlag(cmd)
+	},
 }
 
 func init() {
@@ -53,7 +58,7 @@ func init() {
 	// buildSetPropertyCmd.PersistentFlags().String("foo", "", "A help for foo")
 	buildSetPropertyCmd.PersistentFlags().StringP("project", "p", "", "Name of the project")
 	buildSetPropertyCmd.PersistentFlags().StringP("branch", "b", "", "Name of the branch")
-	buildSetPropertyCmd.PersistentFlags().StringP("build", "n", "", "Name of the build")
+	buildSetPropertyCmd.PersistentFlags().StringP("build", "n", "", buildRefUsage)
 
 	buildSetPropertyCmd.MarkPersistentFlagRequired("project")
 	buildSetPropertyCmd.MarkPersistentFlagRequired("branch")
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/buildref"
+	config "ontrack-cli/config"
+)
+
+var buildShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Displays a build",
+	Long: `Displays a build, with its properties, promotions, validations and links.
+
+The build can be identified by its name:
+
+	ontrack-cli build show -p PROJECT -b BRANCH -n BUILD
+
+or by its ID:
+
+	ontrack-cli build show --id ID
+
+or by reference:
+
+* '@latest' - last build of the branch
+* '@latest:GOLD' - last build of the branch promoted to GOLD
+* '@commit:<sha>' - build of the branch having this Git commit
+* '@release:1.2.3' - build of the branch having this release
+* '#123' - build having this ID, the project and the branch being optional
+
+	ontrack-cli build show -p PROJECT -b BRANCH -n @latest:GOLD
+
+The same references can be used wherever a build is given using '--build', like in 'validate', 'promote',
+'build set-property', 'build wait' or 'validation-run list'.
+
+Only the last validation run of each validation stamp is displayed. The links are displayed in both
+directions: the builds used by this build, and the builds using it.
+
+Use '--output json' or '--output yaml' to get the raw data.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := cmd.Flags().GetInt("id")
+		if err != nil {
+			return err
+		}
+
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		// ID given as a reference
+		ref, err := buildref.Parse(build)
+		if err != nil {
+			return err
+		}
+		if ref.Kind == buildref.KindID && id == 0 {
+			id = ref.ID
+		}
+
+		if id == 0 && (project == "" || branch == "" || build == "") {
+			return errors.New("Either --id or all of --project, --branch and --build are required")
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		var result *client.Build
+		if id != 0 {
+			result, err = client.GetBuildByID(cfg, id)
+		} else {
+			build, err = ResolveBuild(cfg, project, branch, build)
+			if err != nil {
+				return err
+			}
+			result, err = client.GetBuild(cfg, project, branch, build)
+		}
+		if err != nil || result == nil {
+			return err
+		}
+
+		return PrintOutput(cmd, result, func(w io.Writer) error {
+			printBuild(w, result)
+			return nil
+		})
+	},
+}
+
+func printBuild(w io.Writer, build *client.Build) {
+	fmt.Fprintf(w, "ID:\t%d\n", build.ID)
+	fmt.Fprintf(w, "Build:\t%s/%s/%s\n", build.Project, build.Branch, build.Name)
+	if build.Description != "" {
+		fmt.Fprintf(w, "Description:\t%s\n", build.Description)
+	}
+	fmt.Fprintf(w, "Creation:\t%s by %s\n", build.Creation.Time, build.Creation.User)
+	if build.RunInfo != nil {
+		fmt.Fprintf(w, "Source:\t%s %s\n", build.RunInfo.SourceType, build.RunInfo.SourceURI)
+		fmt.Fprintf(w, "Trigger:\t%s %s\n", build.RunInfo.TriggerType, build.RunInfo.TriggerData)
+		fmt.Fprintf(w, "Run time:\t%ds\n", build.RunInfo.RunTime)
+	}
+	if build.Release != "" {
+		fmt.Fprintf(w, "Release:\t%s\n", build.Release)
+	}
+	if build.Commit != "" {
+		fmt.Fprintf(w, "Commit:\t%s\n", build.Commit)
+	}
+
+	fmt.Fprintln(w, "Properties:")
+	for _, property := range build.Properties {
+		fmt.Fprintf(w, "  %s\t%s\n", property.Type, string(property.Value))
+	}
+
+	fmt.Fprintln(w, "Promotions:")
+	for _, run := range build.PromotionRuns {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n",
+			run.PromotionLevel.Name,
+			run.Creation.Time,
+			run.Creation.User,
+			run.Description,
+		)
+	}
+
+	fmt.Fprintln(w, "Validations:")
+	for _, run := range build.ValidationRuns {
+		data := ""
+		if run.Data != nil {
+			data = FormatValidationData(run.Data.Descriptor.ID, run.Data.Data)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n",
+			run.ValidationStamp.Name,
+			run.LastStatus.StatusID.ID,
+			run.Creation.Time,
+			run.Creation.User,
+			data,
+		)
+	}
+
+	fmt.Fprintln(w, "Using:")
+	printBuildLinks(w, build.Using)
+	fmt.Fprintln(w, "Used by:")
+	printBuildLinks(w, build.UsedBy)
+}
+
+func printBuildLinks(w io.Writer, links []client.BuildLink) {
+	for _, link := range links {
+		fmt.Fprintf(w, "  %s/%s/%s\t%d\n", link.Project, link.Branch, link.Name, link.ID)
+	}
+}
+
+func init() {
+	buildCmd.AddCommand(buildShowCmd)
+
+	buildShowCmd.Flags().Int("id", 0, "ID of the build")
+	buildShowCmd.Flags().StringP("project", "p", "", "Name of the project")
+	buildShowCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+	buildShowCmd.Flags().StringP("build", "n", "", buildRefUsage)
+
+	InitOutputCommandFlags(buildShowCmd)
+}
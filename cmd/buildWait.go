@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+// Status of a validation run which stops the wait
+const failedStatus = "FAILED"
+
+var buildWaitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Waits for a build to be promoted or validated",
+	Long: `Waits for a build to be promoted:
+
+	ontrack-cli build wait -p PROJECT -b BRANCH -n BUILD --promotion SILVER
+
+or for the last run of a validation stamp to have a given status (PASSED by default):
+
+	ontrack-cli build wait -p PROJECT -b BRANCH -n BUILD --validation e2e[:PASSED]
+
+The build is checked every '--interval', this interval being multiplied by '--backoff' after each
+check, up to '--max-interval'. The progress is printed on the standard error.
+
+The command exits with:
+
+* 0 - when the build is promoted or validated
+* 5 - when the build is still not promoted or validated after '--timeout'
+* 6 - when the last run of the validation stamp is FAILED, while another status is expected
+
+The errors met while checking the build, like Ontrack being unavailable, are printed and the build is
+checked again until '--timeout'. The command stops at once when the build, the branch or the promotion
+level does not exist.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		promotion, err := cmd.Flags().GetString("promotion")
+		if err != nil {
+			return err
+		}
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		interval, err := cmd.Flags().GetDuration("interval")
+		if err != nil {
+			return err
+		}
+
+		maxInterval, err := cmd.Flags().GetDuration("max-interval")
+		if err != nil {
+			return err
+		}
+
+		backoff, err := cmd.Flags().GetFloat64("backoff")
+		if err != nil {
+			return err
+		}
+
+		timeout, err := cmd.Flags().GetDuration("timeout")
+		if err != nil {
+			return err
+		}
+
+		if (promotion == "") == (validation == "") {
+			return errors.New("Either --promotion or --validation is required")
+		}
+		if interval <= 0 || backoff < 1 {
+			return errors.New("--interval must be positive and --backoff must be at least 1")
+		}
+
+		// Expected status
+		status := passedStatus
+		if index := strings.LastIndex(validation, ":"); index > 0 {
+			validation, status = validation[:index], strings.ToUpper(validation[index+1:])
+		}
+		target := fmt.Sprintf("build %s to be promoted to %s", build, promotion)
+		if validation != "" {
+			target = fmt.Sprintf("build %s to be validated as %s for %s", build, status, validation)
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+		if cfg.Disabled {
+			return nil
+		}
+
+		// Build reference
+		build, err = ResolveBuild(cfg, project, branch, build)
+		if err != nil {
+			return err
+		}
+
+		// The usage is not relevant for a timeout or a failure
+		cmd.SilenceUsage = true
+
+		deadline := time.Now().Add(timeout)
+		for attempt := 1; ; attempt++ {
+			current, err := waitCurrentState(cfg, project, branch, build, promotion, validation)
+			if errors.Is(err, client.ErrNotFound) {
+				return err
+			}
+			if err != nil {
+				// Transient error, like Ontrack being restarted, checked again until the deadline
+				fmt.Fprintf(os.Stderr, "Error while checking %s: %s\n", target, strings.TrimSpace(err.Error()))
+			} else if current == status || (promotion != "" && current != "") {
+				fmt.Fprintf(os.Stderr, "Done waiting for %s\n", target)
+				return nil
+			}
+			if validation != "" && current == failedStatus {
+				return NewExitError(ExitCodeWaitFailed, "Validation %s of build %s is %s", validation, build, current)
+			}
+
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return NewExitError(ExitCodeWaitTimeout, "Timeout after %s waiting for %s", timeout, target)
+			}
+			delay := interval
+			if delay > remaining {
+				delay = remaining
+			}
+			switch {
+			case err != nil:
+				current = "error"
+			case promotion != "":
+				current = "not promoted"
+			case current == "":
+				current = "not validated"
+			}
+			fmt.Fprintf(os.Stderr, "Waiting for %s (attempt %d, %s, next check in %s)\n", target, attempt, current, delay.Round(time.Millisecond))
+			time.Sleep(delay)
+
+			// Backoff
+			interval = time.Duration(float64(interval) * backoff)
+			if maxInterval > 0 && interval > maxInterval {
+				interval = maxInterval
+			}
+		}
+	},
+}
+
+// waitCurrentState returns the name of the promotion level if the build is promoted, or the status
+// of the last run of the validation stamp. An empty string is returned when the build has no such run.
+func waitCurrentState(cfg *config.Config, project string, branch string, build string, promotion string, validation string) (string, error) {
+	if promotion != "" {
+		runs, err := client.GetBuildPromotionRuns(cfg, project, branch, build, promotion)
+		if err != nil || len(runs) == 0 {
+			return "", err
+		}
+		return promotion, nil
+	}
+	run, err := client.GetLastValidationRun(cfg, project, branch, build, validation)
+	if err != nil || run == nil {
+		return "", err
+	}
+	return run.LastStatus.StatusID.ID, nil
+}
+
+func init() {
+	buildCmd.AddCommand(buildWaitCmd)
+
+	buildWaitCmd.Flags().StringP("project", "p", "", "Name of the project")
+	buildWaitCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+	buildWaitCmd.Flags().StringP("build", "n", "", buildRefUsage)
+	buildWaitCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level to wait for")
+	buildWaitCmd.Flags().StringP("validation", "v", "", "Name of the validation stamp to wait for, optionally followed by the expected status, like 'e2e:PASSED'")
+	buildWaitCmd.Flags().Duration("interval", 10*time.Second, "Initial interval between two checks")
+	buildWaitCmd.Flags().Duration("max-interval", 2*time.Minute, "Maximum interval between two checks (0 for no limit)")
+	buildWaitCmd.Flags().Float64("backoff", 1.5, "Factor applied to the interval after each check")
+	buildWaitCmd.Flags().Duration("timeout", 30*time.Minute, "Maximum time to wait")
+
+	buildWaitCmd.MarkFlagRequired("project")
+	buildWaitCmd.MarkFlagRequired("branch")
+	buildWaitCmd.MarkFlagRequired("build")
+}
@@ -0,0 +1,104 @@
+package buildref
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	client "ontrack-cli/client"
+)
+
+// Kinds of build references
+const (
+	KindName    = "name"
+	KindID      = "id"
+	KindLatest  = "latest"
+	KindCommit  = "commit"
+	KindRelease = "release"
+)
+
+// Ref is a reference to a build, either its name or a symbolic selector:
+//
+//	@latest         last build of the branch
+//	@latest:GOLD    last build of the branch promoted to GOLD
+//	@commit:<sha>   build having this Git commit
+//	@release:1.2.3  build having this release
+//	#123            build having this ID
+type Ref struct {
+	Kind string
+	// Name of the build, promotion level, commit or release
+	Value string
+	ID    int
+	Text  string
+}
+
+// Parse reads a build reference. Any text not starting with @ or # is a build name.
+func Parse(text string) (Ref, error) {
+	ref := Ref{Kind: KindName, Value: text, Text: text}
+	switch {
+	case strings.HasPrefix(text, "#"):
+		id, err := strconv.Atoi(text[1:])
+		if err != nil || id <= 0 {
+			return ref, fmt.Errorf("invalid build reference %s: the ID must be a positive number", text)
+		}
+		ref.Kind = KindID
+		ref.Value = ""
+		ref.ID = id
+	case strings.HasPrefix(text, "@"):
+		kind, value, hasValue := strings.Cut(text[1:], ":")
+		switch kind {
+		case KindLatest:
+			if hasValue && value == "" {
+				return ref, fmt.Errorf("invalid build reference %s: the promotion level is missing", text)
+			}
+		case KindCommit, KindRelease:
+			if value == "" {
+				return ref, fmt.Errorf("invalid build reference %s: a value is expected after @%s:", text, kind)
+			}
+		default:
+			return ref, fmt.Errorf("invalid build reference %s: must be @latest, @latest:<promotion>, @commit:<sha>, @release:<release> or #<id>", text)
+		}
+		ref.Kind = kind
+		ref.Value = value
+	}
+	return ref, nil
+}
+
+// Form returns the branch search form (StandardBuildFilter) looking for the build. The count is
+// enough to detect an ambiguous reference.
+func (r Ref) Form() map[string]interface{} {
+	switch r.Kind {
+	case KindLatest:
+		form := map[string]interface{}{"count": 1}
+		if r.Value != "" {
+			form["withPromotionLevel"] = r.Value
+		}
+		return form
+	case KindCommit:
+		return map[string]interface{}{
+			"count":             10,
+			"withProperty":      client.GitCommitPropertyType,
+			"withPropertyValue": r.Value,
+		}
+	case KindRelease:
+		return map[string]interface{}{
+			"count":             10,
+			"withProperty":      client.ReleasePropertyType,
+			"withPropertyValue": r.Value,
+		}
+	default:
+		return nil
+	}
+}
+
+// Select returns the name of the build among the results of the search
+func (r Ref) Select(names []string, project string, branch string) (string, error) {
+	switch {
+	case len(names) == 0:
+		return "", fmt.Errorf("no build found for %s in %s/%s", r.Text, project, branch)
+	case len(names) > 1 && r.Kind != KindLatest:
+		return "", fmt.Errorf("%s is ambiguous in %s/%s, matching builds: %s", r.Text, project, branch, strings.Join(names, ", "))
+	default:
+		return names[0], nil
+	}
+}
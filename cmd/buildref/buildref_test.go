@@ -0,0 +1,56 @@
+package buildref
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		text     string
+		expected Ref
+	}{
+		{"42", Ref{Kind: KindName, Value: "42"}},
+		{"1.0-rc", Ref{Kind: KindName, Value: "1.0-rc"}},
+		{"#123", Ref{Kind: KindID, ID: 123}},
+		{"@latest", Ref{Kind: KindLatest}},
+		{"@latest:GOLD", Ref{Kind: KindLatest, Value: "GOLD"}},
+		{"@commit:bae524d", Ref{Kind: KindCommit, Value: "bae524d"}},
+		{"@release:1.2.3", Ref{Kind: KindRelease, Value: "1.2.3"}},
+	}
+
+	for _, test := range tests {
+		test.expected.Text = test.text
+		actual, err := Parse(test.text)
+		if err != nil {
+			t.Errorf("%s - Error: %v", test.text, err)
+		} else if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s - Expected: %v, Actual: %v", test.text, test.expected, actual)
+		}
+	}
+
+	for _, text := range []string{"#", "#abc", "#0", "@", "@oldest", "@latest:", "@commit", "@commit:", "@release"} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("%s - Expected an error", text)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	latest, _ := Parse("@latest")
+	commit, _ := Parse("@commit:abc")
+
+	if name, err := latest.Select([]string{"3", "2"}, "P", "main"); err != nil || name != "3" {
+		t.Errorf("@latest - Expected 3, Actual: %s, %v", name, err)
+	}
+	if name, err := commit.Select([]string{"3"}, "P", "main"); err != nil || name != "3" {
+		t.Errorf("@commit - Expected 3, Actual: %s, %v", name, err)
+	}
+	if _, err := commit.Select([]string{"3", "2"}, "P", "main"); err == nil || err.Error() != "@commit:abc is ambiguous in P/main, matching builds: 3, 2" {
+		t.Errorf("@commit - Expected an ambiguity error, Actual: %v", err)
+	}
+	if _, err := latest.Select(nil, "P", "main"); err == nil || err.Error() != "no build found for @latest in P/main" {
+		t.Errorf("@latest - Expected a missing error, Actual: %v", err)
+	}
+}
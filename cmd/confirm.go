@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// InitConfirmCommandFlags registers the `yes` flag for a command asking for a confirmation
+func InitConfirmCommandFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("yes", false, "Does not ask for a confirmation (for scripts). Without it, the command fails when the confirmation is declined or cannot be asked")
+}
+
+// Confirm asks the user to confirm an action, unless the `yes` flag is set.
+// Anything else than 'y' or 'yes' cancels the action. An error is returned when
+// no confirmation can be asked, the standard input not being a terminal.
+func Confirm(cmd *cobra.Command, message string) (bool, error) {
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return false, err
+	}
+	if yes {
+		return true, nil
+	}
+	// The usage is not relevant for a missing confirmation
+	cmd.SilenceUsage = true
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false, errors.New("confirmation required, use --yes")
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", message)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		// No input available
+		fmt.Fprintln(os.Stderr)
+		return false, errors.New("confirmation required, use --yes")
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
+
+// ErrCancelled is returned when the user does not confirm an action
+var ErrCancelled = errors.New("cancelled")
@@ -0,0 +1,159 @@
+package datatypes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DataType is a validation data type known by the CLI
+type DataType struct {
+	// FQCN of the data type
+	ID string
+	// Short alias, like "tests"
+	Alias string
+	// Schema of the configuration of the validation stamp, nil if the type has no configuration
+	Config *Schema
+	// Schema of the data of the validation runs
+	Data Schema
+}
+
+const generalPrefix = "net.nemerosa.ontrack.extension.general.validation."
+
+// Levels of the CHML data type
+var chmlLevels = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+func thresholdConfig() *Schema {
+	return &Schema{Kind: KindObject, Fields: []Field{
+		{Name: "warningThreshold", Schema: Schema{Kind: KindInt}},
+		{Name: "failureThreshold", Schema: Schema{Kind: KindInt}},
+		{Name: "okIfGreater", Schema: Schema{Kind: KindBoolean}},
+	}}
+}
+
+func chmlLevel() Schema {
+	return Schema{Kind: KindObject, Fields: []Field{
+		{Name: "level", Required: true, Schema: Schema{Kind: KindEnum, Enum: chmlLevels}},
+		{Name: "value", Required: true, Schema: Schema{Kind: KindInt}},
+	}}
+}
+
+func percentage() Schema {
+	min, max := intRange(0, 100)
+	return Schema{Kind: KindInt, Min: min, Max: max}
+}
+
+// DataTypes are the validation data types provided by Ontrack
+var DataTypes = []DataType{
+	{
+		ID:    generalPrefix + "TestSummaryValidationDataType",
+		Alias: "tests",
+		Config: &Schema{Kind: KindObject, Fields: []Field{
+			{Name: "warningIfSkipped", Schema: Schema{Kind: KindBoolean}},
+		}},
+		Data: Schema{Kind: KindObject, Fields: []Field{
+			{Name: "passed", Required: true, Schema: Schema{Kind: KindInt}},
+			{Name: "skipped", Required: true, Schema: Schema{Kind: KindInt}},
+			{Name: "failed", Required: true, Schema: Schema{Kind: KindInt}},
+		}},
+	},
+	{
+		ID:    generalPrefix + "CHMLValidationDataType",
+		Alias: "chml",
+		Config: &Schema{Kind: KindObject, Fields: []Field{
+			{Name: "warningLevel", Required: true, Schema: chmlLevel()},
+			{Name: "failedLevel", Required: true, Schema: chmlLevel()},
+		}},
+		Data: Schema{Kind: KindObject, Fields: []Field{
+			{Name: "levels", Required: true, Schema: Schema{Kind: KindMap, Values: &Schema{Kind: KindInt}}},
+		}},
+	},
+	{
+		ID:     generalPrefix + "ThresholdPercentageValidationDataType",
+		Alias:  "percentage",
+		Config: thresholdConfig(),
+		Data:   percentage(),
+	},
+	{
+		ID:     generalPrefix + "ThresholdNumberValidationDataType",
+		Alias:  "number",
+		Config: thresholdConfig(),
+		Data:   Schema{Kind: KindInt},
+	},
+	{
+		ID:     generalPrefix + "FractionValidationDataType",
+		Alias:  "fraction",
+		Config: thresholdConfig(),
+		Data: Schema{Kind: KindObject, Fields: []Field{
+			{Name: "numerator", Required: true, Schema: Schema{Kind: KindInt}},
+			{Name: "denominator", Required: true, Schema: Schema{Kind: KindInt}},
+		}},
+	},
+	{
+		ID:    generalPrefix + "MetricsValidationDataType",
+		Alias: "metrics",
+		Data: Schema{Kind: KindObject, Fields: []Field{
+			{Name: "metrics", Required: true, Schema: Schema{Kind: KindMap, Values: &Schema{Kind: KindNumber}}},
+		}},
+	},
+	{
+		ID:    generalPrefix + "TextValidationDataType",
+		Alias: "text",
+		Config: &Schema{Kind: KindObject, Fields: []Field{
+			{Name: "passedIfRegex", Schema: Schema{Kind: KindString}},
+			{Name: "warningIfRegex", Schema: Schema{Kind: KindString}},
+			{Name: "failedIfRegex", Schema: Schema{Kind: KindString}},
+		}},
+		Data: Schema{Kind: KindString},
+	},
+}
+
+// Find returns the data type having the given alias or FQCN, or nil if not known
+func Find(idOrAlias string) *DataType {
+	for index, dataType := range DataTypes {
+		if dataType.ID == idOrAlias || strings.EqualFold(dataType.Alias, idOrAlias) {
+			return &DataTypes[index]
+		}
+	}
+	return nil
+}
+
+// Resolve returns the FQCN for an alias. Any other value is returned as is.
+func Resolve(idOrAlias string) string {
+	if dataType := Find(idOrAlias); dataType != nil {
+		return dataType.ID
+	}
+	return idOrAlias
+}
+
+// ValidateData parses some validation data and checks it against the schema of its type.
+// The data of an unknown type is only parsed.
+func ValidateData(idOrAlias string, text string) (interface{}, error) {
+	value, err := ParseValue(text)
+	if err != nil {
+		return nil, err
+	}
+	if dataType := Find(idOrAlias); dataType != nil {
+		if err := dataType.Data.Validate(value); err != nil {
+			return nil, fmt.Errorf("invalid data for %s: %w", dataType.Alias, err)
+		}
+	}
+	return value, nil
+}
+
+// ValidateConfig parses the configuration of a data type and checks it against its schema.
+// The configuration of an unknown type is only parsed.
+func ValidateConfig(idOrAlias string, text string) (interface{}, error) {
+	value, err := ParseValue(text)
+	if err != nil {
+		return nil, err
+	}
+	if dataType := Find(idOrAlias); dataType != nil {
+		if dataType.Config == nil {
+			return nil, fmt.Errorf("%s does not accept any configuration", dataType.Alias)
+		}
+		if err := dataType.Config.Validate(value); err != nil {
+			return nil, fmt.Errorf("invalid configuration for %s: %w", dataType.Alias, err)
+		}
+	}
+	return value, nil
+}
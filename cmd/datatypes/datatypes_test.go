@@ -0,0 +1,88 @@
+package datatypes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseValue(t *testing.T) {
+
+	tests := []struct {
+		text     string
+		expected interface{}
+	}{
+		{`{"passed": 1, "skipped": 2, "failed": 3}`, map[string]interface{}{"passed": 1.0, "skipped": 2.0, "failed": 3.0}},
+		{`{passed: 1, skipped: 2, failed: 3}`, map[string]interface{}{"passed": 1.0, "skipped": 2.0, "failed": 3.0}},
+		{`{warningLevel: {level: HIGH, value: 1}}`, map[string]interface{}{"warningLevel": map[string]interface{}{"level": "HIGH", "value": 1.0}}},
+		{`{text: "Some \"text\"", values: [1, -2.5e1], ok: true, none: null}`, map[string]interface{}{"text": `Some "text"`, "values": []interface{}{1.0, -25.0}, "ok": true, "none": nil}},
+		{`42`, 42.0},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseValue(test.text)
+		if err != nil {
+			t.Errorf("%s - Error: %v", test.text, err)
+		} else if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("%s - Expected: %v, Actual: %v", test.text, test.expected, actual)
+		}
+	}
+
+	for _, text := range []string{`{passed: 1`, `{passed 1}`, `{passed: 1} x`, `"text`} {
+		if _, err := ParseValue(text); err == nil {
+			t.Errorf("%s - Expected an error", text)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	tests := []struct {
+		dataType string
+		data     string
+		valid    bool
+	}{
+		{"tests", `{passed: 1, skipped: 2, failed: 3}`, true},
+		{"tests", `{passed: 1, skipped: 2}`, false},
+		{"tests", `{passed: 1, skipped: 2, failed: 3, other: 4}`, false},
+		{"tests", `{passed: 1.5, skipped: 2, failed: 3}`, false},
+		{"chml", `{levels: {CRITICAL: 1, HIGH: 2}}`, true},
+		{"chml", `{levels: {CRITICAL: "1"}}`, false},
+		{"percentage", `80`, true},
+		{"percentage", `120`, false},
+		{"metrics", `{metrics: {latency: 12.5}}`, true},
+		{"text", `"Some text"`, true},
+		{"text", `{text: "Some text"}`, false},
+		{"net.nemerosa.ontrack.extension.general.validation.FractionValidationDataType", `{numerator: 1, denominator: 2}`, true},
+		{"com.example.UnknownValidationDataType", `{any: "thing"}`, true},
+	}
+
+	for _, test := range tests {
+		_, err := ValidateData(test.dataType, test.data)
+		if test.valid && err != nil {
+			t.Errorf("%s %s - Unexpected error: %v", test.dataType, test.data, err)
+		} else if !test.valid && err == nil {
+			t.Errorf("%s %s - Expected an error", test.dataType, test.data)
+		}
+	}
+
+	if _, err := ValidateConfig("chml", `{warningLevel: {level: HIGH, value: 1}, failedLevel: {level: CRITICAL, value: 1}}`); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := ValidateConfig("chml", `{warningLevel: {level: HIGHER, value: 1}, failedLevel: {level: CRITICAL, value: 1}}`); err == nil {
+		t.Errorf("Expected an error for an unknown level")
+	}
+	if _, err := ValidateConfig("metrics", `{}`); err == nil {
+		t.Errorf("Expected an error for a type without configuration")
+	}
+}
+
+func TestGraphQLLiteral(t *testing.T) {
+	value, err := ParseValue(`{"warningLevel": {"level": "HIGH", "value": 1}, "ok": true}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{ok: true, warningLevel: {level: "HIGH", value: 1}}`
+	if actual := GraphQLLiteral(value); actual != expected {
+		t.Errorf("Expected: %s, Actual: %s", expected, actual)
+	}
+}
@@ -0,0 +1,156 @@
+package datatypes
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Kinds of values in a schema
+const (
+	KindObject  = "object"
+	KindMap     = "map"
+	KindInt     = "int"
+	KindNumber  = "number"
+	KindString  = "string"
+	KindBoolean = "boolean"
+	KindEnum    = "enum"
+)
+
+// Schema describes the expected shape of some validation data or configuration
+type Schema struct {
+	Kind string
+	// Fields of an object
+	Fields []Field
+	// Type of the values of a map
+	Values *Schema
+	// Values of an enum
+	Enum []string
+	// Range of an int
+	Min, Max *int
+}
+
+// Field of an object schema
+type Field struct {
+	Name     string
+	Required bool
+	Schema   Schema
+}
+
+func intRange(min int, max int) (*int, *int) {
+	return &min, &max
+}
+
+// String returns a compact representation of the schema, like {passed: int, skipped: int, failed: int}
+func (s Schema) String() string {
+	switch s.Kind {
+	case KindObject:
+		var fields []string
+		for _, field := range s.Fields {
+			name := field.Name
+			if !field.Required {
+				name += "?"
+			}
+			fields = append(fields, name+": "+field.Schema.String())
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case KindMap:
+		return "{<key>: " + s.Values.String() + "}"
+	case KindEnum:
+		return strings.Join(s.Enum, "|")
+	case KindInt:
+		if s.Min != nil && s.Max != nil {
+			return fmt.Sprintf("int(%d..%d)", *s.Min, *s.Max)
+		}
+	}
+	return s.Kind
+}
+
+// Validate checks a value, as parsed by ParseValue, against the schema
+func (s Schema) Validate(value interface{}) error {
+	return s.validate("", value)
+}
+
+func (s Schema) validate(path string, value interface{}) error {
+	at := ""
+	if path != "" {
+		at = " at " + path
+	}
+	switch s.Kind {
+	case KindObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("an object is expected%s", at)
+		}
+		var names []string
+		for _, field := range s.Fields {
+			names = append(names, field.Name)
+			fieldValue, present := object[field.Name]
+			if !present || fieldValue == nil {
+				if field.Required {
+					return fmt.Errorf("%s is required", join(path, field.Name))
+				}
+				continue
+			}
+			if err := field.Schema.validate(join(path, field.Name), fieldValue); err != nil {
+				return err
+			}
+		}
+		var unknown []string
+		for name := range object {
+			if !slices.Contains(names, name) {
+				unknown = append(unknown, join(path, name))
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("unknown field(s) %s, expected %s", strings.Join(unknown, ", "), s.String())
+		}
+	case KindMap:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("an object is expected%s", at)
+		}
+		for key, item := range object {
+			if err := s.Values.validate(join(path, key), item); err != nil {
+				return err
+			}
+		}
+	case KindInt, KindNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("a number is expected%s", at)
+		}
+		if s.Kind == KindInt {
+			if number != math.Trunc(number) {
+				return fmt.Errorf("an integer is expected%s", at)
+			}
+			if s.Min != nil && s.Max != nil && (number < float64(*s.Min) || number > float64(*s.Max)) {
+				return fmt.Errorf("a value between %d and %d is expected%s", *s.Min, *s.Max, at)
+			}
+		}
+	case KindString:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("a string is expected%s", at)
+		}
+	case KindBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("a boolean is expected%s", at)
+		}
+	case KindEnum:
+		text, ok := value.(string)
+		if !ok || !slices.Contains(s.Enum, text) {
+			return fmt.Errorf("one of %s is expected%s", strings.Join(s.Enum, ", "), at)
+		}
+	}
+	return nil
+}
+
+func join(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
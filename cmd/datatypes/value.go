@@ -0,0 +1,207 @@
+package datatypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseValue parses some validation data or configuration, given either as JSON
+// or using the GraphQL literal notation, where the keys of objects are not quoted
+// and where enum values are bare words, like {level: HIGH, value: 1}.
+func ParseValue(text string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		return value, nil
+	}
+	p := &parser{input: text}
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+	return value, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("cannot parse %q at position %d: %s", p.input, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && (unicode.IsSpace(rune(p.input[p.pos])) || p.input[p.pos] == ',') {
+		p.pos++
+	}
+}
+
+func (p *parser) value() (interface{}, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("unexpected end")
+	}
+	switch c := p.input[p.pos]; {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.list()
+	case c == '"':
+		return p.str()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.number()
+	default:
+		word := p.word()
+		switch word {
+		case "":
+			return nil, p.errorf("unexpected %q", c)
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			// Enum value
+			return word, nil
+		}
+	}
+}
+
+func (p *parser) object() (interface{}, error) {
+	object := make(map[string]interface{})
+	p.pos++
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("missing }")
+		}
+		if p.input[p.pos] == '}' {
+			p.pos++
+			return object, nil
+		}
+		var key string
+		if p.input[p.pos] == '"' {
+			quoted, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			key = quoted.(string)
+		} else {
+			key = p.word()
+			if key == "" {
+				return nil, p.errorf("expected a key")
+			}
+		}
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != ':' {
+			return nil, p.errorf("expected : after %s", key)
+		}
+		p.pos++
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		object[key] = value
+	}
+}
+
+func (p *parser) list() (interface{}, error) {
+	list := []interface{}{}
+	p.pos++
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) {
+			return nil, p.errorf("missing ]")
+		}
+		if p.input[p.pos] == ']' {
+			p.pos++
+			return list, nil
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+}
+
+func (p *parser) str() (interface{}, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			return strconv.Unquote(p.input[start:p.pos])
+		default:
+			p.pos++
+		}
+	}
+	return nil, p.errorf("missing \"")
+}
+
+func (p *parser) number() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.input) && strings.ContainsRune("+-.eE0123456789", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %s", p.input[start:p.pos])
+	}
+	return value, nil
+}
+
+func (p *parser) word() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := rune(p.input[p.pos])
+		if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// GraphQLLiteral renders a value, as parsed by ParseValue, using the GraphQL literal notation
+func GraphQLLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		var keys []string
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var fields []string
+		for _, key := range keys {
+			fields = append(fields, key+": "+GraphQLLiteral(v[key]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, GraphQLLiteral(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
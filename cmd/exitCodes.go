@@ -0,0 +1,33 @@
+package cmd
+
+import "fmt"
+
+// Exit codes returned by the CLI, besides 0 (OK) and 1 (any other error)
+const (
+	// ExitCodeGateFailed is returned when a quality gate is not met
+	ExitCodeGateFailed = 3
+	// ExitCodeCriteriaNotMet is returned when a build does not meet the criteria of a promotion
+	ExitCodeCriteriaNotMet = 4
+	// ExitCodeWaitTimeout is returned when a build is still not promoted or validated after the timeout
+	ExitCodeWaitTimeout = 5
+	// ExitCodeWaitFailed is returned when waiting for a validation which has failed
+	ExitCodeWaitFailed = 6
+)
+
+// ExitError is an error which terminates the CLI with a specific exit code
+type ExitError struct {
+	Code    int
+	Message string
+}
+
+func (e *ExitError) Error() string {
+	return e.Message
+}
+
+// NewExitError creates an error terminating the CLI with the given exit code
+func NewExitError(code int, format string, args ...interface{}) *ExitError {
+	return &ExitError{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
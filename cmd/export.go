@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"ontrack-cli/cmd/manifest"
+	config "ontrack-cli/config"
+)
+
+// Formats of an export
+const (
+	ExportManifest   = "manifest"
+	ExportPromotions = "promotions"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports the setup of a project as a manifest",
+	Long: `Exports the setup of a project as a manifest.
+
+	ontrack-cli export -p PROJECT [-b BRANCH] [-o ontrack.yaml]
+
+The project, its properties, its branches with their properties, validation stamps and promotion levels are
+exported in the format used by 'ontrack-cli apply', restricted to the given branches if any. The validation
+stamps are exported with their data type and configuration, and the promotion levels with their auto promotion.
+
+The validation stamps and promotion levels of one branch can also be exported in the format of the
+.ontrack/promotions.yaml file used by 'ontrack-cli pl auto':
+
+	ontrack-cli export -p PROJECT -b BRANCH --format promotions -o .ontrack/promotions.yaml
+
+The export is written as YAML, or as JSON if the file has a .json extension, and is printed if no file is given.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+		branches, err := cmd.Flags().GetStringSlice("branch")
+		if err != nil {
+			return err
+		}
+		for index, branch := range branches {
+			branches[index] = NormalizeBranchName(branch)
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		// Configuration
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+		state := &clientState{cfg: cfg}
+
+		var data interface{}
+		switch format {
+		case ExportManifest:
+			data, err = manifest.Export(project, branches, state)
+		case ExportPromotions:
+			if len(branches) != 1 {
+				return fmt.Errorf("exactly one branch is needed to export the promotions")
+			}
+			data, err = manifest.ExportPromotions(state, project, branches[0])
+		default:
+			return fmt.Errorf("unsupported export format %q, must be one of %s or %s", format, ExportManifest, ExportPromotions)
+		}
+		if err != nil {
+			return err
+		}
+
+		var content []byte
+		if filepath.Ext(output) == ".json" {
+			content, err = json.MarshalIndent(data, "", "  ")
+			content = append(content, '\n')
+		} else {
+			content, err = yaml.Marshal(data)
+		}
+		if err != nil {
+			return err
+		}
+
+		if output == "" {
+			fmt.Print(string(content))
+			return nil
+		}
+		return os.WriteFile(output, content, 0644)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("project", "p", "", "Name of the project")
+	exportCmd.Flags().StringSliceP("branch", "b", nil, "Names of the branches to export, all by default")
+	exportCmd.Flags().String("format", ExportManifest, "Format of the export: manifest or promotions")
+	exportCmd.Flags().StringP("output", "o", "", "Path of the file to write, printed if not set")
+
+	exportCmd.MarkFlagRequired("project")
+}
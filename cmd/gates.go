@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"ontrack-cli/cmd/gates"
+)
+
+// Names of the values available to the quality gates, per type of validation data
+var (
+	testGateNames       = []string{"passed", "skipped", "failed", "total"}
+	chmlGateNames       = []string{"critical", "high", "medium", "low"}
+	percentageGateNames = []string{"value"}
+)
+
+// InitGateCommandFlags registers the `fail-on` flag for a validation command
+func InitGateCommandFlags(cmd *cobra.Command, example string) {
+	cmd.Flags().StringArray("fail-on", []string{}, fmt.Sprintf(
+		"Quality gate, like '%s'. The command exits with code %d if the expression is true once the validation is recorded (can be repeated)",
+		example,
+		ExitCodeGateFailed,
+	))
+}
+
+// GetGates parses the `fail-on` flag. If names is not empty, the gates can only use these names.
+func GetGates(cmd *cobra.Command, names []string) ([]gates.Gate, error) {
+	expressions, err := cmd.Flags().GetStringArray("fail-on")
+	if err != nil {
+		return nil, err
+	}
+	return gates.Parse(expressions, names)
+}
+
+// CheckGates evaluates the gates against the recorded values and returns an error
+// with the ExitCodeGateFailed exit code if any of them fails.
+func CheckGates(cmd *cobra.Command, list []gates.Gate, values map[string]float64) error {
+	failures := gates.Check(list, values)
+	for _, message := range failures {
+		fmt.Fprintln(os.Stderr, "Quality gate failed:", message)
+	}
+	if len(failures) > 0 {
+		// The usage is not relevant for a failed gate
+		cmd.SilenceUsage = true
+		return NewExitError(ExitCodeGateFailed, "%d quality gate(s) failed", len(failures))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package gates
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ontrack-cli/cmd/metrics"
+)
+
+// Gate is a quality gate evaluated locally once a validation has been recorded
+type Gate struct {
+	// Original expression
+	Expression string
+	// Name of the value to check
+	Name string
+	// Comparison operator
+	Operator string
+	// Value to compare with
+	Threshold float64
+}
+
+var gateExpression = regexp.MustCompile(`^\s*(.+?)\s*(>=|<=|==|!=|>|<)\s*(\S+)\s*$`)
+
+// Parse parses the gate expressions. If names is not empty, the gates can only use these names.
+func Parse(expressions []string, names []string) ([]Gate, error) {
+	var gates []Gate
+	for _, expression := range expressions {
+		match := gateExpression.FindStringSubmatch(expression)
+		if match == nil {
+			return nil, errors.New("Quality gate " + expression + " must match name(>|>=|<|<=|==|!=)number")
+		}
+		name := match[1]
+		if len(names) > 0 && !slices.Contains(names, name) {
+			return nil, fmt.Errorf("Quality gate %s must use one of %s", expression, strings.Join(names, ", "))
+		}
+		threshold, err := metrics.ParseValue(match[3])
+		if err != nil {
+			return nil, fmt.Errorf("Quality gate %s: %w", expression, err)
+		}
+		gates = append(gates, Gate{
+			Expression: expression,
+			Name:       name,
+			Operator:   match[2],
+			Threshold:  threshold,
+		})
+	}
+	return gates, nil
+}
+
+// Failed checks if the gate is not met by the given values, and returns a message describing the check.
+// A gate whose value is missing fails.
+func (g Gate) Failed(values map[string]float64) (bool, string) {
+	value, ok := values[g.Name]
+	if !ok {
+		return true, fmt.Sprintf("%s (%s not found)", g.Expression, g.Name)
+	}
+	var result bool
+	switch g.Operator {
+	case ">":
+		result = value > g.Threshold
+	case ">=":
+		result = value >= g.Threshold
+	case "<":
+		result = value < g.Threshold
+	case "<=":
+		result = value <= g.Threshold
+	case "==":
+		result = value == g.Threshold
+	case "!=":
+		result = value != g.Threshold
+	}
+	return result, fmt.Sprintf("%s (%s=%s)", g.Expression, g.Name, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// Check evaluates the gates against the recorded values and returns the messages of the failed ones
+func Check(gates []Gate, values map[string]float64) []string {
+	var failures []string
+	for _, g := range gates {
+		if failed, message := g.Failed(values); failed {
+			failures = append(failures, message)
+		}
+	}
+	return failures
+}
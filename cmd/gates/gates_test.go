@@ -0,0 +1,104 @@
+package gates
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGatesParsing(t *testing.T) {
+
+	tests := []struct {
+		expression string
+		names      []string
+		expected   Gate
+		err        string
+	}{
+		{"failed > 0", nil, Gate{"failed > 0", "failed", ">", 0}, ""},
+		{"coverage<80.5", nil, Gate{"coverage<80.5", "coverage", "<", 80.5}, ""},
+		{" value >= 1e3 ", []string{"value"}, Gate{" value >= 1e3 ", "value", ">=", 1000}, ""},
+		{"delta <= -2", nil, Gate{"delta <= -2", "delta", "<=", -2}, ""},
+		{"high == 0", []string{"critical", "high"}, Gate{"high == 0", "high", "==", 0}, ""},
+		{"low != 1", nil, Gate{"low != 1", "low", "!=", 1}, ""},
+		{"http_requests_total{code=200,method=post} > 10", nil, Gate{
+			"http_requests_total{code=200,method=post} > 10",
+			"http_requests_total{code=200,method=post}",
+			">",
+			10,
+		}, ""},
+		{"failed", nil, Gate{}, "Quality gate failed must match name(>|>=|<|<=|==|!=)number"},
+		{"> 1", nil, Gate{}, "Quality gate > 1 must match name(>|>=|<|<=|==|!=)number"},
+		{"failed > many", nil, Gate{}, `Quality gate failed > many: "many" is not a number`},
+		{"errors > 0", []string{"passed", "failed"}, Gate{}, "Quality gate errors > 0 must use one of passed, failed"},
+	}
+
+	for _, test := range tests {
+		gates, err := Parse([]string{test.expression}, test.names)
+		if test.err != "" {
+			if err == nil {
+				t.Errorf("%s: expected error %q, got %v", test.expression, test.err, gates)
+			} else if err.Error() != test.err {
+				t.Errorf("%s: expected error %q, got %q", test.expression, test.err, err.Error())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.expression, err)
+			continue
+		}
+		if len(gates) != 1 || !reflect.DeepEqual(gates[0], test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.expression, test.expected, gates)
+		}
+	}
+}
+
+func TestGatesCheck(t *testing.T) {
+
+	values := map[string]float64{
+		"failed":   2,
+		"coverage": 80.5,
+	}
+
+	tests := []struct {
+		expression string
+		failed     bool
+		message    string
+	}{
+		{"failed > 1", true, "failed > 1 (failed=2)"},
+		{"failed > 2", false, "failed > 2 (failed=2)"},
+		{"failed >= 2", true, "failed >= 2 (failed=2)"},
+		{"failed >= 3", false, "failed >= 3 (failed=2)"},
+		{"coverage < 81", true, "coverage < 81 (coverage=80.5)"},
+		{"coverage < 80.5", false, "coverage < 80.5 (coverage=80.5)"},
+		{"coverage <= 80.5", true, "coverage <= 80.5 (coverage=80.5)"},
+		{"coverage <= 80", false, "coverage <= 80 (coverage=80.5)"},
+		{"failed == 2", true, "failed == 2 (failed=2)"},
+		{"failed == 0", false, "failed == 0 (failed=2)"},
+		{"failed != 0", true, "failed != 0 (failed=2)"},
+		{"failed != 2", false, "failed != 2 (failed=2)"},
+		{"skipped > 0", true, "skipped > 0 (skipped not found)"},
+	}
+
+	for _, test := range tests {
+		gates, err := Parse([]string{test.expression}, nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.expression, err)
+			continue
+		}
+		failed, message := gates[0].Failed(values)
+		if failed != test.failed || message != test.message {
+			t.Errorf("%s: expected (%v, %q), got (%v, %q)", test.expression, test.failed, test.message, failed, message)
+		}
+	}
+
+	gates, err := Parse([]string{"failed > 1", "failed > 2", "skipped > 0"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"failed > 1 (failed=2)", "skipped > 0 (skipped not found)"}
+	if failures := Check(gates, values); !reflect.DeepEqual(failures, expected) {
+		t.Errorf("expected failures %v, got %v", expected, failures)
+	}
+	if failures := Check(nil, values); len(failures) != 0 {
+		t.Errorf("expected no failure without gates, got %v", failures)
+	}
+}
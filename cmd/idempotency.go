@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+// Value of the idempotency key flag when used without a value
+const defaultIdempotencyKey = "auto"
+
+// Line added to the description of a validation run to record an explicit idempotency key
+var idempotencyKeyLine = regexp.MustCompile(`(?m)^Idempotency key: (\S+)$`)
+
+// InitIdempotencyCommandFlags registers the flags used to skip the recording of a validation
+// when an equivalent run already exists.
+func InitIdempotencyCommandFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Bool("if-absent", false, "Records the validation only if the build has no run yet for the validation stamp")
+	cmd.PersistentFlags().String("idempotency-key", "", "Records the validation only if the build has no run with the same key for the validation stamp. "+
+		"Without a value (--idempotency-key), the key is computed from the run info trigger data and the validation stamp.")
+	cmd.PersistentFlags().Lookup("idempotency-key").NoOptDefVal = defaultIdempotencyKey
+}
+
+// IdempotentDescription adds the explicit idempotency key, if any, to the description of a validation
+func IdempotentDescription(cmd *cobra.Command, description string) (string, error) {
+	key, err := cmd.Flags().GetString("idempotency-key")
+	if err != nil {
+		return "", err
+	}
+	if key == "" || key == defaultIdempotencyKey {
+		return description, nil
+	}
+	if description != "" {
+		description += "\n"
+	}
+	return description + "Idempotency key: " + key, nil
+}
+
+// SkipValidation checks if the build already has a run equivalent to the validation
+// about to be recorded, according to the idempotency flags. When this is the case,
+// the skipped validation is reported and true is returned. The status is the one about
+// to be recorded, checked only by commands having the `if-status-differs` flag.
+func SkipValidation(
+	cmd *cobra.Command,
+	cfg *config.Config,
+	project string,
+	branch string,
+	build string,
+	validation string,
+	status string,
+	runInfo *client.RunInfo,
+) (bool, error) {
+	ifAbsent, err := cmd.Flags().GetBool("if-absent")
+	if err != nil {
+		return false, err
+	}
+
+	key, err := cmd.Flags().GetString("idempotency-key")
+	if err != nil {
+		return false, err
+	}
+	if key == defaultIdempotencyKey {
+		if runInfo == nil || runInfo.TriggerData == "" {
+			return false, errors.New("--idempotency-key without a value requires --trigger-data")
+		}
+		key = computeIdempotencyKey(runInfo, validation)
+	}
+
+	// Only available on commands having an explicit status
+	ifStatusDiffers := false
+	if cmd.Flags().Lookup("if-status-differs") != nil {
+		ifStatusDiffers, err = cmd.Flags().GetBool("if-status-differs")
+		if err != nil {
+			return false, err
+		}
+	}
+	if !ifStatusDiffers {
+		status = ""
+	} else if status == "" {
+		return false, fmt.Errorf("%s: --if-status-differs requires an explicit status", validation)
+	}
+
+	if !ifAbsent && key == "" && status == "" {
+		return false, nil
+	}
+
+	// Existing runs
+	runs, err := client.GetValidationRuns(cfg, project, branch, build, validation, 50)
+	if err != nil || len(runs) == 0 {
+		return false, err
+	}
+	last := &runs[0]
+	for index := range runs {
+		if runs[index].RunOrder > last.RunOrder {
+			last = &runs[index]
+		}
+	}
+
+	var reason string
+	if ifAbsent {
+		reason = "a run already exists"
+	} else if status != "" && last.LastStatus.StatusID.ID == status {
+		reason = "the status is unchanged"
+	} else if key != "" {
+		for index := range runs {
+			if runIdempotencyKey(&runs[index]) == key {
+				last = &runs[index]
+				reason = "a run with the same idempotency key already exists"
+				break
+			}
+		}
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	fmt.Printf(
+		"Skipped validation %s of build %s: %s (#%d, %s)\n",
+		validation,
+		build,
+		reason,
+		last.ID,
+		last.LastStatus.StatusID.ID,
+	)
+	return true, nil
+}
+
+// computeIdempotencyKey returns the default idempotency key for a validation
+func computeIdempotencyKey(runInfo *client.RunInfo, validation string) string {
+	hash := sha256.Sum256([]byte(runInfo.TriggerType + "\n" + runInfo.TriggerData + "\n" + validation))
+	return hex.EncodeToString(hash[:8])
+}
+
+// runIdempotencyKey returns the idempotency key of an existing run: either the explicit key
+// recorded in its description or the key computed from its run info.
+func runIdempotencyKey(run *client.ValidationRun) string {
+	if match := idempotencyKeyLine.FindStringSubmatch(run.Description); match != nil {
+		return match[1]
+	}
+	if run.RunInfo != nil && run.RunInfo.TriggerData != "" {
+		return computeIdempotencyKey(run.RunInfo, run.ValidationStamp.Name)
+	}
+	return ""
+}
@@ -2,9 +2,12 @@ package junit
 
 import (
 	"encoding/xml"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 func GetSummaryJUnitTestReports(pattern string) (int, int, int, error) {
@@ -61,3 +64,148 @@ func getSummaryJUnitTestReport(path string) (int, int, int, error) {
 
 	return passed, root.Skipped, root.Failures + root.Errors, nil
 }
+
+// Ways to group the test reports
+const (
+	SplitBySuite     = "suite"
+	SplitByPackage   = "package"
+	SplitByDirectory = "directory"
+)
+
+// TestReport is the summary of one test suite
+type TestReport struct {
+	// Path to the report file
+	Path string
+	// Name of the test suite
+	Suite string
+	// Package of the test suite
+	Package string
+	// Counts
+	Passed  int
+	Skipped int
+	Failed  int
+}
+
+// TestGroup is the summary of several test suites
+type TestGroup struct {
+	// Name of the group
+	Module string
+	// Counts
+	Passed  int
+	Skipped int
+	Failed  int
+}
+
+type testSuiteElement struct {
+	XMLName    xml.Name
+	Name       string             `xml:"name,attr"`
+	Package    string             `xml:"package,attr"`
+	Tests      int                `xml:"tests,attr"`
+	Skipped    int                `xml:"skipped,attr"`
+	Failures   int                `xml:"failures,attr"`
+	Errors     int                `xml:"errors,attr"`
+	TestSuites []testSuiteElement `xml:"testsuite"`
+}
+
+// GetJUnitTestReports returns the summary of each test suite in the matching files.
+// Both <testsuite> and <testsuites> root elements are supported.
+func GetJUnitTestReports(pattern string) ([]TestReport, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []TestReport
+	for _, match := range matches {
+		buf, err := os.ReadFile(match)
+		if err != nil {
+			return nil, err
+		}
+		var root testSuiteElement
+		if err := xml.Unmarshal(buf, &root); err != nil {
+			return nil, err
+		}
+		suites := []testSuiteElement{root}
+		if root.XMLName.Local == "testsuites" {
+			suites = root.TestSuites
+		}
+		for _, suite := range suites {
+			reports = append(reports, TestReport{
+				Path:    match,
+				Suite:   suite.Name,
+				Package: suitePackage(suite),
+				Passed:  suite.Tests - suite.Skipped - suite.Failures - suite.Errors,
+				Skipped: suite.Skipped,
+				Failed:  suite.Failures + suite.Errors,
+			})
+		}
+	}
+	return reports, nil
+}
+
+func suitePackage(suite testSuiteElement) string {
+	if suite.Package != "" {
+		return suite.Package
+	}
+	// Suite names are usually fully qualified class names
+	if index := strings.LastIndex(suite.Name, "."); index > 0 {
+		return suite.Name[:index]
+	}
+	return suite.Name
+}
+
+// GroupJUnitTestReports groups the test reports by suite, by package or by directory.
+//
+// When grouping by directory, the name of the group is the first directory after the
+// part of the pattern which does not contain any wildcard. For example, with the
+// "services/*/build/test-results/test/*.xml" pattern, the reports are grouped by service.
+func GroupJUnitTestReports(pattern string, reports []TestReport, splitBy string) ([]TestGroup, error) {
+	groups := make(map[string]*TestGroup)
+	var modules []string
+	for _, report := range reports {
+		var module string
+		switch splitBy {
+		case SplitBySuite:
+			module = report.Suite
+		case SplitByPackage:
+			module = report.Package
+		case SplitByDirectory:
+			module = reportDirectory(pattern, report.Path)
+		default:
+			return nil, fmt.Errorf("cannot split the test reports by %q, must be one of %s, %s or %s", splitBy, SplitBySuite, SplitByPackage, SplitByDirectory)
+		}
+		group, ok := groups[module]
+		if !ok {
+			group = &TestGroup{Module: module}
+			groups[module] = group
+			modules = append(modules, module)
+		}
+		group.Passed += report.Passed
+		group.Skipped += report.Skipped
+		group.Failed += report.Failed
+	}
+
+	sort.Strings(modules)
+	var result []TestGroup
+	for _, module := range modules {
+		result = append(result, *groups[module])
+	}
+	return result, nil
+}
+
+func reportDirectory(pattern string, path string) string {
+	// Static prefix of the pattern
+	var prefix []string
+	for _, part := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if strings.ContainsAny(part, "*?[") {
+			break
+		}
+		prefix = append(prefix, part)
+	}
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	if len(parts) > len(prefix)+1 {
+		return parts[len(prefix)]
+	}
+	// The report is directly in the static part of the pattern
+	return filepath.Base(filepath.Dir(path))
+}
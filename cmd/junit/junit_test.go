@@ -26,3 +26,49 @@ func TestJUnitParsing(t *testing.T) {
 	}
 
 }
+
+func TestJUnitGrouping(t *testing.T) {
+
+	pattern := "junit_modules/*/build/test-results/*.xml"
+
+	reports, err := GetJUnitTestReports(pattern)
+	if err != nil {
+		t.Fatalf("Error reading the JUnit XML reports: %v", err)
+	}
+
+	tests := []struct {
+		splitBy  string
+		expected []TestGroup
+	}{
+		{SplitByDirectory, []TestGroup{
+			{Module: "api", Passed: 2, Skipped: 0, Failed: 1},
+			{Module: "web", Passed: 2, Skipped: 1, Failed: 0},
+		}},
+		{SplitByPackage, []TestGroup{
+			{Module: "com.acme.api", Passed: 2, Skipped: 0, Failed: 1},
+			{Module: "com.acme.web", Passed: 2, Skipped: 1, Failed: 0},
+		}},
+		{SplitBySuite, []TestGroup{
+			{Module: "com.acme.api.UserTest", Passed: 2, Skipped: 0, Failed: 1},
+			{Module: "com.acme.web.FormTest", Passed: 1, Skipped: 0, Failed: 0},
+			{Module: "com.acme.web.PageTest", Passed: 1, Skipped: 1, Failed: 0},
+		}},
+	}
+
+	for _, test := range tests {
+		groups, err := GroupJUnitTestReports(pattern, reports, test.splitBy)
+		if err != nil {
+			t.Errorf("Error grouping by %s: %v", test.splitBy, err)
+			continue
+		}
+		if len(groups) != len(test.expected) {
+			t.Errorf("%s - Expected: %v, Actual: %v", test.splitBy, test.expected, groups)
+			continue
+		}
+		for index, expected := range test.expected {
+			if groups[index] != expected {
+				t.Errorf("%s - Expected: %v, Actual: %v", test.splitBy, expected, groups[index])
+			}
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
+	"ontrack-cli/cmd/promotions"
+)
+
+// Export returns the manifest of a project, restricted to some branches if any is given
+func Export(name string, branches []string, state State) (*Manifest, error) {
+	current, err := state.Project(name)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("project %s not found", name)
+	}
+
+	project := Project{Name: current.Name, Properties: exportProjectProperties(current.Properties)}
+	for _, wanted := range branches {
+		if findBranch(current.Branches, wanted) == nil {
+			return nil, fmt.Errorf("branch %s not found in %s", wanted, name)
+		}
+	}
+	for _, currentBranch := range current.Branches {
+		if len(branches) > 0 && !slices.Contains(branches, currentBranch.Name) {
+			continue
+		}
+		config, err := ExportPromotions(state, current.Name, currentBranch.Name)
+		if err != nil {
+			return nil, err
+		}
+		project.Branches = append(project.Branches, Branch{
+			Name:        currentBranch.Name,
+			Properties:  exportBranchProperties(currentBranch.Properties),
+			Validations: config.Validations,
+			Promotions:  config.Promotions,
+		})
+	}
+	return &Manifest{Projects: []Project{project}}, nil
+}
+
+// ExportPromotions returns the validation stamps and promotion levels of a branch,
+// in the format of the promotions file
+func ExportPromotions(state State, project string, branch string) (*promotions.Config, error) {
+	stamps, err := state.ValidationStamps(project, branch)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := state.PromotionLevels(project, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &promotions.Config{}
+	for _, stamp := range stamps {
+		config.Validations = append(config.Validations, exportValidation(stamp))
+	}
+	for _, level := range levels {
+		promotion := promotions.Promotion{Name: level.Name, Description: level.Description}
+		if auto := level.AutoPromotion; auto != nil {
+			promotion.Validations = auto.ValidationStamps
+			promotion.Promotions = auto.PromotionLevels
+			promotion.Include = auto.Include
+			promotion.Exclude = auto.Exclude
+		}
+		config.Promotions = append(config.Promotions, promotion)
+	}
+	return config, nil
+}
+
+// exportValidation converts a validation stamp, using the dedicated types when possible
+func exportValidation(stamp client.ValidationStamp) promotions.Validation {
+	validation := promotions.Validation{Name: stamp.Name, Description: stamp.Description}
+	if stamp.DataType == nil {
+		return validation
+	}
+	dataType := stamp.DataType.Descriptor.ID
+	config := stamp.DataType.Config
+
+	alias := dataType
+	if known := datatypes.Find(dataType); known != nil {
+		alias = known.Alias
+	}
+	switch alias {
+	case "tests":
+		tests := &promotions.TestsConfig{}
+		if convert(config, tests) == nil {
+			validation.Tests = tests
+			return validation
+		}
+	case "chml":
+		chml := &promotions.CHMLConfig{}
+		if convert(config, chml) == nil {
+			validation.CHML = chml
+			return validation
+		}
+	case "percentage":
+		percentage := &promotions.PercentageConfig{}
+		if convert(config, percentage) == nil {
+			validation.Percentage = percentage
+			return validation
+		}
+	case "metrics":
+		validation.Metrics = true
+		return validation
+	}
+
+	// Generic data type, also when the configuration does not fit the dedicated type
+	validation.DataType = &alias
+	if normalized := promotions.Normalize(config); normalized != nil {
+		literal := datatypes.GraphQLLiteral(normalized)
+		validation.DataTypeConfig = &literal
+	}
+	return validation
+}
+
+// convert decodes a JSON value into a typed configuration, rejecting the unknown fields
+func convert(value interface{}, target interface{}) error {
+	if value == nil {
+		return nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+func exportProjectProperties(properties []client.Property) *ProjectProperties {
+	if len(properties) == 0 {
+		return nil
+	}
+	result := &ProjectProperties{}
+	for _, property := range properties {
+		switch property.Type {
+		case client.GitHubPropertyType:
+			if repository := exportRepository(property.Value); repository != nil {
+				result.GitHub = repository
+				continue
+			}
+		case client.BitbucketCloudPropertyType:
+			if repository := exportRepository(property.Value); repository != nil {
+				result.BitbucketCloud = repository
+				continue
+			}
+		case client.AutoValidationStampPropertyType:
+			var value struct {
+				IsAutoCreate                bool
+				IsAutoCreateIfNotPredefined bool
+			}
+			if json.Unmarshal(property.Value, &value) == nil {
+				result.AutoValidationStamps = &AutoValidationStampsProperty{
+					AutoCreate:                &value.IsAutoCreate,
+					AutoCreateIfNotPredefined: value.IsAutoCreateIfNotPredefined,
+				}
+				continue
+			}
+		case client.AutoPromotionLevelPropertyType:
+			var value struct {
+				IsAutoCreate bool
+			}
+			if json.Unmarshal(property.Value, &value) == nil {
+				result.AutoPromotionLevels = &AutoPromotionLevelsProperty{AutoCreate: &value.IsAutoCreate}
+				continue
+			}
+		}
+		// Any other property, or a property which cannot be read, as is
+		result.Generic = addGeneric(result.Generic, property)
+	}
+	return result
+}
+
+func exportRepository(raw json.RawMessage) *RepositoryProperty {
+	var value struct {
+		Configuration struct {
+			Name string
+		}
+		Repository                          string
+		IndexationInterval                  int
+		IssueServiceConfigurationIdentifier string
+	}
+	if err := json.Unmarshal(raw, &value); err != nil || value.Repository == "" {
+		return nil
+	}
+	return &RepositoryProperty{
+		Configuration: value.Configuration.Name,
+		Repository:    value.Repository,
+		Indexation:    value.IndexationInterval,
+		IssueService:  value.IssueServiceConfigurationIdentifier,
+	}
+}
+
+func exportBranchProperties(properties []client.Property) *BranchProperties {
+	if len(properties) == 0 {
+		return nil
+	}
+	result := &BranchProperties{}
+	for _, property := range properties {
+		if property.Type == client.GitBranchPropertyType {
+			var value struct {
+				Branch string
+			}
+			if json.Unmarshal(property.Value, &value) == nil {
+				result.Git = &GitBranchProperty{Branch: value.Branch}
+				continue
+			}
+		}
+		result.Generic = addGeneric(result.Generic, property)
+	}
+	return result
+}
+
+// addGeneric adds a property as compact JSON
+func addGeneric(generic map[string]string, property client.Property) map[string]string {
+	if generic == nil {
+		generic = make(map[string]string)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, property.Value); err != nil {
+		generic[property.Type] = string(property.Value)
+	} else {
+		generic[property.Type] = compact.String()
+	}
+	return generic
+}
+
+func findBranch(branches []client.Branch, name string) *client.Branch {
+	for index := range branches {
+		if branches[index].Name == name {
+			return &branches[index]
+		}
+	}
+	return nil
+}
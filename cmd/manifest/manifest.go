@@ -0,0 +1,220 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ontrack-cli/cmd/datatypes"
+	"ontrack-cli/cmd/promotions"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Manifest describes projects, their branches, validation stamps and promotion levels,
+// as found in an ontrack.yaml file
+type Manifest struct {
+	Projects []Project `yaml:"projects" json:"projects"`
+}
+
+type Project struct {
+	// Name of the project
+	Name string `yaml:"name" json:"name"`
+	// Properties of the project
+	Properties *ProjectProperties `yaml:"properties,omitempty" json:"properties,omitempty"`
+	// Branches of the project
+	Branches []Branch `yaml:"branches,omitempty" json:"branches,omitempty"`
+}
+
+type ProjectProperties struct {
+	// GitHub repository
+	GitHub *RepositoryProperty `yaml:"github,omitempty" json:"github,omitempty"`
+	// Bitbucket Cloud repository
+	BitbucketCloud *RepositoryProperty `yaml:"bitbucketCloud,omitempty" json:"bitbucketCloud,omitempty"`
+	// Auto creation of validation stamps
+	AutoValidationStamps *AutoValidationStampsProperty `yaml:"autoValidationStamps,omitempty" json:"autoValidationStamps,omitempty"`
+	// Auto creation of promotion levels
+	AutoPromotionLevels *AutoPromotionLevelsProperty `yaml:"autoPromotionLevels,omitempty" json:"autoPromotionLevels,omitempty"`
+	// Other properties, as JSON or GraphQL literals indexed by the FQCN of their type
+	Generic map[string]string `yaml:"generic,omitempty" json:"generic,omitempty"`
+}
+
+type RepositoryProperty struct {
+	// Name of the configuration in Ontrack
+	Configuration string `yaml:"configuration" json:"configuration"`
+	// Name of the repository
+	Repository string `yaml:"repository" json:"repository"`
+	// Indexation interval, in minutes
+	Indexation int `yaml:"indexation,omitempty" json:"indexation,omitempty"`
+	// Issue service identifier, like "jira//name"
+	IssueService string `yaml:"issueService,omitempty" json:"issueService,omitempty"`
+}
+
+type AutoValidationStampsProperty struct {
+	// Creation of the predefined validation stamps, true by default
+	AutoCreate *bool `yaml:"autoCreate,omitempty" json:"autoCreate,omitempty"`
+	// Creation of the validation stamps even if they are not predefined
+	AutoCreateIfNotPredefined bool `yaml:"autoCreateIfNotPredefined,omitempty" json:"autoCreateIfNotPredefined,omitempty"`
+}
+
+type AutoPromotionLevelsProperty struct {
+	// Creation of the predefined promotion levels, true by default
+	AutoCreate *bool `yaml:"autoCreate,omitempty" json:"autoCreate,omitempty"`
+}
+
+type Branch struct {
+	// Name of the branch in Ontrack
+	Name string `yaml:"name" json:"name"`
+	// Properties of the branch
+	Properties *BranchProperties `yaml:"properties,omitempty" json:"properties,omitempty"`
+	// Validation stamps, like in the promotions.yaml file
+	Validations []promotions.Validation `yaml:"validations,omitempty" json:"validations,omitempty"`
+	// Promotion levels, like in the promotions.yaml file
+	Promotions []promotions.Promotion `yaml:"promotions,omitempty" json:"promotions,omitempty"`
+}
+
+type BranchProperties struct {
+	// Git branch
+	Git *GitBranchProperty `yaml:"git,omitempty" json:"git,omitempty"`
+	// Other properties, as JSON or GraphQL literals indexed by the FQCN of their type
+	Generic map[string]string `yaml:"generic,omitempty" json:"generic,omitempty"`
+}
+
+type GitBranchProperty struct {
+	// Name of the Git branch, defaults to the name of the branch
+	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+}
+
+// IsAutoCreate returns the auto creation flag, true by default
+func (p *AutoValidationStampsProperty) IsAutoCreate() bool {
+	return p.AutoCreate == nil || *p.AutoCreate
+}
+
+// IsAutoCreate returns the auto creation flag, true by default
+func (p *AutoPromotionLevelsProperty) IsAutoCreate() bool {
+	return p.AutoCreate == nil || *p.AutoCreate
+}
+
+// PromotionsConfig returns the validations and promotions of the branch, as a promotions file
+func (b *Branch) PromotionsConfig() *promotions.Config {
+	return &promotions.Config{Validations: b.Validations, Promotions: b.Promotions}
+}
+
+// Read reads and checks a manifest file, in YAML or JSON
+func Read(path string) (*Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, content)
+}
+
+var yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// Parse decodes and checks the content of a manifest. Unknown fields are rejected.
+// The returned error is a *promotions.Error listing the problems.
+func Parse(path string, content []byte) (*Manifest, error) {
+	var manifest Manifest
+	if err := yaml.UnmarshalStrict(content, &manifest); err != nil {
+		var problems []promotions.Problem
+		for _, text := range strings.Split(err.Error(), "\n") {
+			text = strings.TrimSpace(text)
+			if text == "" || text == "yaml: unmarshal errors:" {
+				continue
+			}
+			if match := yamlLinePattern.FindStringSubmatch(text); match != nil {
+				line, _ := strconv.Atoi(match[1])
+				problems = append(problems, promotions.Problem{Line: line, Message: match[2]})
+			} else {
+				problems = append(problems, promotions.Problem{Message: strings.TrimPrefix(text, "yaml: ")})
+			}
+		}
+		return nil, &promotions.Error{File: path, Problems: problems}
+	}
+	if problems := manifest.check(); len(problems) > 0 {
+		return nil, &promotions.Error{File: path, Problems: problems}
+	}
+	return &manifest, nil
+}
+
+// check returns the problems of the manifest: missing names, duplicates, incomplete
+// or invalid properties, and the problems of the validations and promotions of the branches
+func (m *Manifest) check() []promotions.Problem {
+	var problems []promotions.Problem
+	report := func(format string, args ...interface{}) {
+		problems = append(problems, promotions.Problem{Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(m.Projects) == 0 {
+		report("no project is defined")
+	}
+	projects := make(map[string]bool)
+	for _, project := range m.Projects {
+		if project.Name == "" {
+			report("a project has no name")
+			continue
+		}
+		if projects[project.Name] {
+			report("project %s is defined several times", project.Name)
+		}
+		projects[project.Name] = true
+
+		if properties := project.Properties; properties != nil {
+			if repository := properties.GitHub; repository != nil && (repository.Configuration == "" || repository.Repository == "") {
+				report("project %s: github needs a configuration and a repository", project.Name)
+			}
+			if repository := properties.BitbucketCloud; repository != nil && (repository.Configuration == "" || repository.Repository == "") {
+				report("project %s: bitbucketCloud needs a configuration and a repository", project.Name)
+			}
+			for _, message := range checkGeneric(properties.Generic) {
+				report("project %s: %s", project.Name, message)
+			}
+		}
+
+		branches := make(map[string]bool)
+		for _, branch := range project.Branches {
+			if branch.Name == "" {
+				report("project %s: a branch has no name", project.Name)
+				continue
+			}
+			if branches[branch.Name] {
+				report("project %s: branch %s is defined several times", project.Name, branch.Name)
+			}
+			branches[branch.Name] = true
+
+			if branch.Properties != nil {
+				for _, message := range checkGeneric(branch.Properties.Generic) {
+					report("project %s, branch %s: %s", project.Name, branch.Name, message)
+				}
+			}
+			if len(branch.Validations) > 0 || len(branch.Promotions) > 0 {
+				for _, problem := range branch.PromotionsConfig().Check() {
+					report("project %s, branch %s: %s", project.Name, branch.Name, problem.Message)
+				}
+			}
+		}
+	}
+	return problems
+}
+
+func checkGeneric(generic map[string]string) []string {
+	var messages []string
+	for _, propertyType := range sortedKeys(generic) {
+		if _, err := datatypes.ParseValue(generic[propertyType]); err != nil {
+			messages = append(messages, fmt.Sprintf("invalid value for the %s property: %v", propertyType, err))
+		}
+	}
+	return messages
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	var keys []string
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
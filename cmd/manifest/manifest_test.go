@@ -0,0 +1,152 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/promotions"
+)
+
+func TestManifestProblems(t *testing.T) {
+	_, err := Read("manifest_files/invalid.yaml")
+	var problems *promotions.Error
+	if !errors.As(err, &problems) {
+		t.Fatalf("Expected problems, got %v", err)
+	}
+	expected := []string{
+		"project my-project: github needs a configuration and a repository",
+		"project my-project, branch main: promotion BRONZE depends on the undefined promotion SILVER",
+		"project my-project: branch main is defined several times",
+		"project my-project is defined several times",
+	}
+	if len(problems.Problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got:\n%v", len(expected), err)
+	}
+	for index, message := range expected {
+		if actual := problems.Problems[index].Message; actual != message {
+			t.Errorf("Expected %q, Actual %q", message, actual)
+		}
+	}
+}
+
+// testState is the state of Ontrack, with an existing main branch
+type testState struct{}
+
+func (s *testState) Project(name string) (*client.Project, error) {
+	return &client.Project{
+		ID:   1,
+		Name: name,
+		Properties: []client.Property{
+			{Type: client.GitHubPropertyType, Value: json.RawMessage(`{"configuration": {"name": "GitHub"}, "repository": "my-org/my-project", "indexationInterval": 0, "issueServiceConfigurationIdentifier": null}`)},
+			{Type: client.AutoPromotionLevelPropertyType, Value: json.RawMessage(`{"isAutoCreate": false}`)},
+		},
+		Branches: []client.Branch{
+			{ID: 2, Name: "main", Properties: []client.Property{
+				{Type: client.GitBranchPropertyType, Value: json.RawMessage(`{"branch": "main", "override": false}`)},
+			}},
+		},
+	}, nil
+}
+
+func (s *testState) ValidationStamps(project string, branch string) ([]client.ValidationStamp, error) {
+	return []client.ValidationStamp{{ID: 1, Name: "unit-tests"}}, nil
+}
+
+func (s *testState) PromotionLevels(project string, branch string) ([]client.PromotionLevel, error) {
+	return nil, nil
+}
+
+func TestManifestPlan(t *testing.T) {
+	m, err := Read("manifest_files/ontrack.yaml")
+	if err != nil {
+		t.Fatalf("Error reading the manifest: %v", err)
+	}
+	changes, err := Plan(m, &testState{})
+	if err != nil {
+		t.Fatalf("Error planning: %v", err)
+	}
+	expected := []struct {
+		resource string
+		name     string
+		action   string
+		details  int
+	}{
+		{ResourceProject, "my-project", promotions.ActionNone, 0},
+		{ResourceProperty, PropertyGitHub, promotions.ActionNone, 0},
+		{ResourceProperty, PropertyAutoPromotionLevels, promotions.ActionUpdate, 1},
+		{ResourceProperty, "net.nemerosa.ontrack.extension.general.MetaInfoPropertyType", promotions.ActionCreate, 0},
+		{ResourceBranch, "main", promotions.ActionNone, 0},
+		{ResourceProperty, PropertyGit, promotions.ActionNone, 0},
+		{ResourceValidation, "unit-tests", promotions.ActionNone, 0},
+		{ResourceValidation, "lint", promotions.ActionCreate, 0},
+		{ResourcePromotion, "BRONZE", promotions.ActionCreate, 0},
+		{ResourceBranch, "develop", promotions.ActionCreate, 0},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for index, change := range changes {
+		e := expected[index]
+		if change.Resource != e.resource || change.Name != e.name || change.Action != e.action || len(change.Details) != e.details {
+			t.Errorf("Expected %+v, Actual %+v", e, change)
+		}
+	}
+}
+
+func TestManifestExport(t *testing.T) {
+	m, err := Export("my-project", nil, &testState{})
+	if err != nil {
+		t.Fatalf("Error exporting: %v", err)
+	}
+	project := m.Projects[0]
+	if github := project.Properties.GitHub; github == nil || github.Configuration != "GitHub" || github.Repository != "my-org/my-project" {
+		t.Errorf("GitHub property not exported: %+v", github)
+	}
+	if auto := project.Properties.AutoPromotionLevels; auto == nil || auto.IsAutoCreate() {
+		t.Errorf("Auto promotion levels property not exported: %+v", auto)
+	}
+	if len(project.Branches) != 1 || project.Branches[0].Properties.Git.Branch != "main" {
+		t.Fatalf("Branch not exported: %+v", project.Branches)
+	}
+	if validations := project.Branches[0].Validations; len(validations) != 1 || validations[0].Name != "unit-tests" {
+		t.Errorf("Validations not exported: %+v", validations)
+	}
+
+	// The export is a valid manifest, without any change to apply
+	changes, err := Plan(m, &testState{})
+	if err != nil {
+		t.Fatalf("Error planning: %v", err)
+	}
+	if count := promotions.Count(changes, promotions.ActionNone); count != len(changes) {
+		t.Errorf("Expected no change, got %+v", changes)
+	}
+
+	if _, err := Export("my-project", []string{"develop"}, &testState{}); err == nil || err.Error() != "branch develop not found in my-project" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestManifestExportValidation(t *testing.T) {
+	var stamps []client.ValidationStamp
+	if err := json.Unmarshal([]byte(`[
+		{"name": "security", "dataType": {
+			"descriptor": {"id": "net.nemerosa.ontrack.extension.general.validation.CHMLValidationDataType"},
+			"config": {"warningLevel": {"level": "HIGH", "value": 1}, "failedLevel": {"level": "CRITICAL", "value": 1}}
+		}},
+		{"name": "ratio", "dataType": {
+			"descriptor": {"id": "net.nemerosa.ontrack.extension.general.validation.FractionValidationDataType"},
+			"config": {"warningThreshold": 90, "failureThreshold": null, "okIfGreater": true}
+		}}
+	]`), &stamps); err != nil {
+		t.Fatal(err)
+	}
+	if security := exportValidation(stamps[0]); security.CHML == nil || security.CHML.WarningLevel.Level != "HIGH" {
+		t.Errorf("CHML validation not exported: %+v", security)
+	}
+	ratio := exportValidation(stamps[1])
+	if ratio.DataType == nil || *ratio.DataType != "fraction" || ratio.DataTypeConfig == nil || *ratio.DataTypeConfig != "{okIfGreater: true, warningThreshold: 90}" {
+		t.Errorf("Generic validation not exported: %+v", ratio)
+	}
+}
@@ -0,0 +1,278 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
+	"ontrack-cli/cmd/promotions"
+)
+
+// Kinds of resources in a plan
+const (
+	ResourceProject    = "project"
+	ResourceBranch     = "branch"
+	ResourceProperty   = "property"
+	ResourceValidation = promotions.KindValidation
+	ResourcePromotion  = promotions.KindPromotion
+)
+
+// Names of the properties set by dedicated mutations
+const (
+	PropertyGitHub               = "github"
+	PropertyBitbucketCloud       = "bitbucketCloud"
+	PropertyAutoValidationStamps = "autoValidationStamps"
+	PropertyAutoPromotionLevels  = "autoPromotionLevels"
+	PropertyGit                  = "git"
+)
+
+// Change is a planned change for a resource of the manifest
+type Change struct {
+	Resource string `json:"resource" yaml:"resource"`
+	Project  string `json:"project" yaml:"project"`
+	Branch   string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	// Name of the resource. For a property, either the name of a dedicated property
+	// like "github", or the FQCN of a generic property.
+	Name   string `json:"name" yaml:"name"`
+	Action string `json:"action" yaml:"action"`
+	// What is updated
+	Details []string `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// State gives access to the current projects, validation stamps and promotion levels
+type State interface {
+	// Project returns nil if the project does not exist
+	Project(name string) (*client.Project, error)
+	ValidationStamps(project string, branch string) ([]client.ValidationStamp, error)
+	PromotionLevels(project string, branch string) ([]client.PromotionLevel, error)
+}
+
+// Plan compares the manifest with the current state. Nothing is ever deleted.
+func Plan(m *Manifest, state State) ([]Change, error) {
+	var changes []Change
+	for _, project := range m.Projects {
+		current, err := state.Project(project.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		// Project
+		change := Change{Resource: ResourceProject, Project: project.Name, Name: project.Name, Action: promotions.ActionNone}
+		var currentProperties []client.Property
+		if current == nil {
+			change.Action = promotions.ActionCreate
+		} else {
+			currentProperties = current.Properties
+		}
+		changes = append(changes, change)
+
+		// Project properties
+		for _, property := range project.Properties.desired() {
+			changes = append(changes, property.plan(project.Name, "", currentProperties))
+		}
+
+		// Branches
+		for _, branch := range project.Branches {
+			change := Change{Resource: ResourceBranch, Project: project.Name, Branch: branch.Name, Name: branch.Name, Action: promotions.ActionCreate}
+			var currentBranch *client.Branch
+			if current != nil {
+				for index := range current.Branches {
+					if current.Branches[index].Name == branch.Name {
+						currentBranch = &current.Branches[index]
+						change.Action = promotions.ActionNone
+					}
+				}
+			}
+			changes = append(changes, change)
+
+			// Branch properties
+			currentProperties = nil
+			if currentBranch != nil {
+				currentProperties = currentBranch.Properties
+			}
+			for _, property := range branch.Properties.desired(branch.Name) {
+				changes = append(changes, property.plan(project.Name, branch.Name, currentProperties))
+			}
+
+			// Validation stamps & promotion levels
+			var stamps []client.ValidationStamp
+			var levels []client.PromotionLevel
+			if currentBranch != nil {
+				if stamps, err = state.ValidationStamps(project.Name, branch.Name); err != nil {
+					return nil, err
+				}
+				if levels, err = state.PromotionLevels(project.Name, branch.Name); err != nil {
+					return nil, err
+				}
+			}
+			planned, err := promotions.Plan(branch.PromotionsConfig(), stamps, levels, false)
+			if err != nil {
+				return nil, fmt.Errorf("project %s, branch %s: %w", project.Name, branch.Name, err)
+			}
+			for _, item := range planned {
+				changes = append(changes, Change{
+					Resource: item.Kind,
+					Project:  project.Name,
+					Branch:   branch.Name,
+					Name:     item.Name,
+					Action:   item.Action,
+					Details:  item.Details,
+				})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// PlannedAction returns the action of the change
+func (c Change) PlannedAction() string {
+	return c.Action
+}
+
+// PrintPlan prints the changes, indented by project and branch, followed by a summary
+func PrintPlan(w io.Writer, changes []Change) {
+	for _, change := range changes {
+		indent := "  "
+		switch {
+		case change.Resource == ResourceProject:
+			indent = ""
+		case change.Branch != "" && change.Resource != ResourceBranch:
+			indent = "    "
+		}
+		fmt.Fprintf(w, "%s %s%s %s\n", promotions.ActionSymbols[change.Action], indent, change.Resource, change.Name)
+		for _, detail := range change.Details {
+			fmt.Fprintf(w, "  %s    %s\n", indent, detail)
+		}
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d unchanged.\n",
+		promotions.Count(changes, promotions.ActionCreate),
+		promotions.Count(changes, promotions.ActionUpdate),
+		promotions.Count(changes, promotions.ActionNone),
+	)
+}
+
+// desiredProperty is the expected value of a property
+type desiredProperty struct {
+	name         string
+	propertyType string
+	// Fields of the value which are compared with the current one
+	fields map[string]interface{}
+	// Value of a generic property
+	value interface{}
+}
+
+// desired returns the properties of a project, in a fixed order
+func (p *ProjectProperties) desired() []desiredProperty {
+	if p == nil {
+		return nil
+	}
+	var properties []desiredProperty
+	repository := func(name string, propertyType string, repository *RepositoryProperty) {
+		if repository != nil {
+			properties = append(properties, desiredProperty{name: name, propertyType: propertyType, fields: map[string]interface{}{
+				"configuration":                       repository.Configuration,
+				"repository":                          repository.Repository,
+				"indexationInterval":                  repository.Indexation,
+				"issueServiceConfigurationIdentifier": repository.IssueService,
+			}})
+		}
+	}
+	repository(PropertyGitHub, client.GitHubPropertyType, p.GitHub)
+	repository(PropertyBitbucketCloud, client.BitbucketCloudPropertyType, p.BitbucketCloud)
+	if p.AutoValidationStamps != nil {
+		properties = append(properties, desiredProperty{name: PropertyAutoValidationStamps, propertyType: client.AutoValidationStampPropertyType, fields: map[string]interface{}{
+			"isAutoCreate":                p.AutoValidationStamps.IsAutoCreate(),
+			"isAutoCreateIfNotPredefined": p.AutoValidationStamps.AutoCreateIfNotPredefined,
+		}})
+	}
+	if p.AutoPromotionLevels != nil {
+		properties = append(properties, desiredProperty{name: PropertyAutoPromotionLevels, propertyType: client.AutoPromotionLevelPropertyType, fields: map[string]interface{}{
+			"isAutoCreate": p.AutoPromotionLevels.IsAutoCreate(),
+		}})
+	}
+	return append(properties, generic(p.Generic)...)
+}
+
+// desired returns the properties of a branch, in a fixed order
+func (p *BranchProperties) desired(branch string) []desiredProperty {
+	if p == nil {
+		return nil
+	}
+	var properties []desiredProperty
+	if p.Git != nil {
+		gitBranch := p.Git.Branch
+		if gitBranch == "" {
+			gitBranch = branch
+		}
+		properties = append(properties, desiredProperty{name: PropertyGit, propertyType: client.GitBranchPropertyType, fields: map[string]interface{}{
+			"branch": gitBranch,
+		}})
+	}
+	return append(properties, generic(p.Generic)...)
+}
+
+func generic(values map[string]string) []desiredProperty {
+	var properties []desiredProperty
+	for _, propertyType := range sortedKeys(values) {
+		// Values already checked
+		value, _ := datatypes.ParseValue(values[propertyType])
+		property := desiredProperty{name: propertyType, propertyType: propertyType, value: value}
+		if fields, ok := value.(map[string]interface{}); ok {
+			property.fields = fields
+		}
+		properties = append(properties, property)
+	}
+	return properties
+}
+
+// plan compares a property with its current value. Only the fields of the desired value
+// are compared, the current value possibly containing more information.
+func (p *desiredProperty) plan(project string, branch string, current []client.Property) Change {
+	change := Change{Resource: ResourceProperty, Project: project, Branch: branch, Name: p.name, Action: promotions.ActionCreate}
+	raw := client.FindProperty(current, p.propertyType)
+	if raw == nil {
+		return change
+	}
+	change.Action = promotions.ActionNone
+
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		change.Action = promotions.ActionUpdate
+		return change
+	}
+	fields, isObject := value.(map[string]interface{})
+	if p.fields == nil || !isObject {
+		if actual, desired := promotions.WithoutNulls(value), promotions.Normalize(p.value); !reflect.DeepEqual(actual, desired) {
+			change.Action = promotions.ActionUpdate
+			change.Details = append(change.Details, fmt.Sprintf("value: %s -> %s", promotions.Literal(actual), promotions.Literal(desired)))
+		}
+		return change
+	}
+	for _, key := range sortedKeys(p.fields) {
+		desired := promotions.Normalize(p.fields[key])
+		actual := fields[key]
+		// Configurations are returned as objects
+		if configuration, ok := actual.(map[string]interface{}); ok && key == "configuration" {
+			actual = configuration["name"]
+		}
+		if !sameValue(actual, desired) {
+			change.Action = promotions.ActionUpdate
+			change.Details = append(change.Details, fmt.Sprintf("%s: %s -> %s", key, promotions.Literal(actual), promotions.Literal(desired)))
+		}
+	}
+	return change
+}
+
+// sameValue compares two JSON values, a missing value being the same as an empty one
+func sameValue(actual interface{}, desired interface{}) bool {
+	if reflect.DeepEqual(actual, desired) {
+		return true
+	}
+	empty := func(value interface{}) bool {
+		return value == nil || value == "" || value == float64(0) || value == false
+	}
+	return empty(actual) && empty(desired)
+}
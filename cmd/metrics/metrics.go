@@ -0,0 +1,319 @@
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported file formats
+const (
+	FormatPrometheus = "prometheus"
+	FormatJSON       = "json"
+	FormatCSV        = "csv"
+	FormatGoBench    = "gobench"
+)
+
+// Formats lists all the supported file formats
+var Formats = []string{
+	FormatPrometheus,
+	FormatJSON,
+	FormatCSV,
+	FormatGoBench,
+}
+
+// Metric is a named value
+type Metric struct {
+	Name  string
+	Value float64
+}
+
+// ParseValue parses a metric value, accepting any finite float representation
+// (negative numbers, exponents...)
+func ParseValue(value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", value)
+	}
+	return number, nil
+}
+
+// metricArg is a metric given as name=number
+var metricArg = regexp.MustCompile(`^(.+)=(.+)$`)
+
+// ParseMetric parses a metric given as name=number. Commas are rejected, being
+// the separators of the lists of metrics.
+func ParseMetric(value string) (Metric, error) {
+	match := metricArg.FindStringSubmatch(value)
+	if match == nil || strings.Contains(value, ",") {
+		return Metric{}, fmt.Errorf("Metric %s must match name=number", value)
+	}
+	number, err := ParseValue(match[2])
+	if err != nil {
+		return Metric{}, fmt.Errorf("Metric %s must match name=number: %w", value, err)
+	}
+	return Metric{Name: match[1], Value: number}, nil
+}
+
+// ParseFiles parses all the given files and returns their metrics.
+// If format is empty, the format of each file is detected from its extension or its content.
+func ParseFiles(paths []string, format string) ([]Metric, error) {
+	var metrics []Metric
+	for _, path := range paths {
+		fileMetrics, err := ParseFile(path, format)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, fileMetrics...)
+	}
+	return metrics, nil
+}
+
+// ParseFile parses a file and returns its metrics.
+// If format is empty, the format is detected from the extension or the content of the file.
+func ParseFile(path string, format string) ([]Metric, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	buf, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		format = detectFormat(path, buf)
+	}
+
+	var metrics []Metric
+	switch format {
+	case FormatPrometheus:
+		metrics, err = parsePrometheus(buf)
+	case FormatJSON:
+		metrics, err = parseJSON(buf)
+	case FormatCSV:
+		metrics, err = parseCSV(buf)
+	case FormatGoBench:
+		metrics, err = parseGoBench(buf)
+	default:
+		return nil, fmt.Errorf("unsupported metrics format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return metrics, nil
+}
+
+var goBenchLine = regexp.MustCompile(`^(Benchmark\S+)\s+(\d+)\s+(.*)$`)
+
+func detectFormat(path string, buf []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".csv":
+		return FormatCSV
+	}
+	if trimmed := bytes.TrimSpace(buf); len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		if goBenchLine.MatchString(scanner.Text()) {
+			return FormatGoBench
+		}
+	}
+	return FormatPrometheus
+}
+
+// Prometheus text exposition format
+
+var prometheusLine = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)(\{(.*)\})?\s+(\S+)(\s+-?\d+)?$`)
+var prometheusLabel = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)\s*=\s*"((?:[^"\\]|\\.)*)"`)
+
+func parsePrometheus(buf []byte) ([]Metric, error) {
+	var metrics []Metric
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		match := prometheusLine.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("line %d: cannot parse %q", lineNumber, line)
+		}
+		value, err := strconv.ParseFloat(match[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %q is not a number", lineNumber, match[4])
+		}
+		// Samples like NaN quantiles cannot be recorded
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		name := match[1]
+		if labels := prometheusLabel.FindAllStringSubmatch(match[3], -1); len(labels) > 0 {
+			var pairs []string
+			for _, label := range labels {
+				pairs = append(pairs, label[1]+"="+label[2])
+			}
+			sort.Strings(pairs)
+			name += "{" + strings.Join(pairs, ",") + "}"
+		}
+		metrics = append(metrics, Metric{Name: name, Value: value})
+	}
+	return metrics, scanner.Err()
+}
+
+// JSON objects, nested objects being flattened using dots
+
+func parseJSON(buf []byte) ([]Metric, error) {
+	var root map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	if err := decoder.Decode(&root); err != nil {
+		return nil, err
+	}
+	var metrics []Metric
+	if err := flattenJSON("", root, &metrics); err != nil {
+		return nil, err
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics, nil
+}
+
+func flattenJSON(prefix string, object map[string]interface{}, metrics *[]Metric) error {
+	for key, item := range object {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		switch value := item.(type) {
+		case map[string]interface{}:
+			if err := flattenJSON(name, value, metrics); err != nil {
+				return err
+			}
+		case json.Number:
+			number, err := ParseValue(value.String())
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			*metrics = append(*metrics, Metric{Name: name, Value: number})
+		case string:
+			number, err := ParseValue(value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			*metrics = append(*metrics, Metric{Name: name, Value: number})
+		default:
+			return fmt.Errorf("%s: value must be a number or an object", name)
+		}
+	}
+	return nil
+}
+
+// Two-column CSV: name, value (with an optional header line)
+
+func parseCSV(buf []byte) ([]Metric, error) {
+	reader := csv.NewReader(bytes.NewReader(buf))
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var metrics []Metric
+	for index, record := range records {
+		value, err := ParseValue(record[1])
+		if err != nil {
+			// Header line
+			if index == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", index+1, err)
+		}
+		metrics = append(metrics, Metric{Name: strings.TrimSpace(record[0]), Value: value})
+	}
+	return metrics, nil
+}
+
+// Output of go test -bench, one metric per benchmark and unit
+
+var goBenchProcs = regexp.MustCompile(`-\d+$`)
+
+func parseGoBench(buf []byte) ([]Metric, error) {
+	var metrics []Metric
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		match := goBenchLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		name := goBenchProcs.ReplaceAllString(strings.TrimPrefix(match[1], "Benchmark"), "")
+		// Pairs of value & unit
+		fields := strings.Fields(match[3])
+		for index := 0; index+1 < len(fields); index += 2 {
+			value, err := ParseValue(fields[index])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", match[1], err)
+			}
+			unit := strings.ReplaceAll(fields[index+1], "/", "_per_")
+			metrics = append(metrics, Metric{Name: name + "." + unit, Value: value})
+		}
+	}
+	return metrics, scanner.Err()
+}
+
+// Filter keeps the metrics whose name matches the include regular expression (if not empty)
+// and does not match the exclude one (if not empty)
+func Filter(metrics []Metric, include string, exclude string) ([]Metric, error) {
+	var includeRegex, excludeRegex *regexp.Regexp
+	var err error
+	if include != "" {
+		if includeRegex, err = regexp.Compile(include); err != nil {
+			return nil, err
+		}
+	}
+	if exclude != "" {
+		if excludeRegex, err = regexp.Compile(exclude); err != nil {
+			return nil, err
+		}
+	}
+	var kept []Metric
+	for _, metric := range metrics {
+		if includeRegex != nil && !includeRegex.MatchString(metric.Name) {
+			continue
+		}
+		if excludeRegex != nil && excludeRegex.MatchString(metric.Name) {
+			continue
+		}
+		kept = append(kept, metric)
+	}
+	return kept, nil
+}
+
+// Prefix prepends a prefix to the name of all metrics
+func Prefix(metrics []Metric, prefix string) []Metric {
+	for index := range metrics {
+		metrics[index].Name = prefix + metrics[index].Name
+	}
+	return metrics
+}
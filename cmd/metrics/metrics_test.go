@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"testing"
+)
+
+func TestMetricsParsing(t *testing.T) {
+
+	tests := []struct {
+		file     string
+		expected []Metric
+	}{
+		{"metrics_files/metrics.prom", []Metric{
+			{"http_requests_total{code=200,method=post}", 1027},
+			{"http_requests_total{code=400,method=post}", 3},
+			{"rpc_duration_seconds_sum", 1.7560473e+07},
+			{"temperature_delta", -3.5},
+		}},
+		{"metrics_files/metrics.json", []Metric{
+			{"coverage", 87.5},
+			{"delta", -2},
+			{"size.binary", 1.2e6},
+			{"size.docker", 45000000},
+		}},
+		{"metrics_files/metrics.csv", []Metric{
+			{"speed", 1.5},
+			{"acceleration", -0.25},
+			{"weight", 1000},
+		}},
+		{"metrics_files/bench.txt", []Metric{
+			{"Parse.ns_per_op", 2345},
+			{"Parse.B_per_op", 512},
+			{"Parse.allocs_per_op", 7},
+			{"Parse/small.ns_per_op", 1020.5},
+		}},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseFile(test.file, "")
+		if err != nil {
+			t.Errorf("Error reading the %s file: %v", test.file, err)
+			continue
+		}
+		if len(actual) != len(test.expected) {
+			t.Errorf("%s - Expected: %v, Actual: %v", test.file, test.expected, actual)
+			continue
+		}
+		for index, expected := range test.expected {
+			if actual[index] != expected {
+				t.Errorf("%s - Expected: %v, Actual: %v", test.file, expected, actual[index])
+			}
+		}
+	}
+}
+
+func TestMetricsValue(t *testing.T) {
+
+	valid := map[string]float64{
+		"12":     12,
+		"-12.5":  -12.5,
+		"1e-3":   0.001,
+		"+2.5E2": 250,
+	}
+	for value, expected := range valid {
+		actual, err := ParseValue(value)
+		if err != nil {
+			t.Errorf("%s - Unexpected error: %v", value, err)
+		} else if actual != expected {
+			t.Errorf("%s - Expected: %v, Actual: %v", value, expected, actual)
+		}
+	}
+
+	for _, value := range []string{"", "abc", "NaN", "+Inf"} {
+		if _, err := ParseValue(value); err == nil {
+			t.Errorf("%s - Expected an error", value)
+		}
+	}
+}
+
+func TestMetricsFilterAndPrefix(t *testing.T) {
+
+	metrics, err := ParseFile("metrics_files/bench.txt", FormatGoBench)
+	if err != nil {
+		t.Fatalf("Error reading the file: %v", err)
+	}
+
+	metrics, err = Filter(metrics, `ns_per_op$`, `/small`)
+	if err != nil {
+		t.Fatalf("Error filtering the metrics: %v", err)
+	}
+	metrics = Prefix(metrics, "bench.")
+
+	if len(metrics) != 1 || metrics[0].Name != "bench.Parse.ns_per_op" {
+		t.Errorf("Expected: [bench.Parse.ns_per_op], Actual: %v", metrics)
+	}
+}
+
+func TestMetricsArgument(t *testing.T) {
+
+	tests := []struct {
+		value    string
+		expected Metric
+	}{
+		{"speed=1.5", Metric{"speed", 1.5}},
+		{"delta=-2e3", Metric{"delta", -2000}},
+		{"a=b=2", Metric{"a=b", 2}},
+	}
+
+	for _, test := range tests {
+		actual, err := ParseMetric(test.value)
+		if err != nil {
+			t.Errorf("%s - Error: %v", test.value, err)
+		} else if actual != test.expected {
+			t.Errorf("%s - Expected: %v, Actual: %v", test.value, test.expected, actual)
+		}
+	}
+
+	// A list of metrics is not a single metric
+	for _, value := range []string{"a=1,b=2", "speed", "speed=", "speed=fast"} {
+		if _, err := ParseMetric(value); err == nil {
+			t.Errorf("%s - Expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats for the commands displaying data
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+// InitOutputCommandFlags registers the `output` flag for a command displaying data
+func InitOutputCommandFlags(cmd *cobra.Command) {
+	cmd.Flags().String("output", OutputTable, "Output format: table, json or yaml")
+}
+
+// PrintOutput prints the data as JSON or YAML, according to the `output` flag,
+// or calls the table function to display it for humans.
+func PrintOutput(cmd *cobra.Command, data interface{}, table func(w io.Writer) error) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	switch output {
+	case OutputJSON:
+		buf, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(buf))
+		return nil
+	case OutputYAML:
+		buf, err := yaml.Marshal(data)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(buf))
+		return nil
+	case OutputTable:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if err := table(w); err != nil {
+			return err
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unsupported output format %q, must be one of %s, %s or %s", output, OutputTable, OutputJSON, OutputYAML)
+	}
+}
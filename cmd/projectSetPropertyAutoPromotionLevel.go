@@ -61,42 +61,7 @@ The '--auto-create' option can be used to disable this behaviour altogether:
 		}
 
 		// Data
-		var data struct {
-			SetProjectAutoPromotionLevelProperty struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-
-		// GraphQL call
-		if err := client.GraphQLCall(cfg, `
-		mutation SetProjectAutoPromotionLevelProperty(
-			$project: String!,
-			$autoCreate: Boolean!
-		) {
-			setProjectAutoPromotionLevelProperty(input: {
-				project: $project,
-				isAutoCreate: $autoCreate
-			}) {
-				errors {
-					message
-				}
-			}
-		}
-	`, map[string]interface{}{
-			"project":    project,
-			"autoCreate": autoCreate,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetProjectAutoPromotionLevelProperty.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return client.SetProjectAutoPromotionLevelProperty(cfg, project, autoCreate)
 	},
 }
 
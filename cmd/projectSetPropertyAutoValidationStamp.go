@@ -70,45 +70,7 @@ The '--auto-create' option can be used to disable this behaviour altogether:
 		}
 
 		// Data
-		var data struct {
-			SetProjectAutoValidationStampProperty struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-
-		// GraphQL call
-		if err := client.GraphQLCall(cfg, `
-			mutation SetProjectAutoValidationStampProperty(
-				$project: String!,
-				$autoCreate: Boolean!,
-				$autoCreateIfNotPredefined: Boolean!
-			) {
-				setProjectAutoValidationStampProperty(input: {
-					project: $project,
-					isAutoCreate: $autoCreate,
-					isAutoCreateIfNotPredefined: $autoCreateIfNotPredefined
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":                   project,
-			"autoCreate":                autoCreate,
-			"autoCreateIfNotPredefined": autoCreateIfNotPredefined,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetProjectAutoValidationStampProperty.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return client.SetProjectAutoValidationStampProperty(cfg, project, autoCreate, autoCreateIfNotPredefined)
 	},
 }
 
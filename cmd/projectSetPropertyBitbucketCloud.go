@@ -70,49 +70,7 @@ Example:
 			return err
 		}
 
-		var data struct {
-			SetProjectBitbucketCloudConfigurationProperty struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-		if err := client.GraphQLCall(cfg, `
-			mutation SetProjectBitbucketCloudConfigurationProperty(
-				$project: String!,
-				$configuration: String!,
-				$repository: String!,
-				$indexationInterval: Int,
-				$issueServiceConfigurationIdentifier: String
-			) {
-				setProjectBitbucketCloudConfigurationProperty(input: {
-					project: $project,
-					configuration: $configuration,
-					repository: $repository,
-					indexationInterval: $indexationInterval,
-					issueServiceConfigurationIdentifier: $issueServiceConfigurationIdentifier
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":                             project,
-			"configuration":                       configuration,
-			"repository":                          repository,
-			"indexationInterval":                  indexation,
-			"issueServiceConfigurationIdentifier": issueService,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetProjectBitbucketCloudConfigurationProperty.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return client.SetProjectBitbucketCloudProperty(cfg, project, configuration, repository, indexation, issueService)
 	},
 }
 
@@ -69,49 +69,7 @@ Example:
 			return err
 		}
 
-		var data struct {
-			SetProjectGitHubConfigurationProperty struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-		if err := client.GraphQLCall(cfg, `
-			mutation SetProjectGitHubProperty(
-				$project: String!,
-				$configuration: String!,
-				$repository: String!,
-				$indexationInterval: Int,
-				$issueServiceConfigurationIdentifier: String
-			) {
-				setProjectGitHubConfigurationProperty(input: {
-					project: $project,
-					configuration: $configuration,
-					repository: $repository,
-					indexationInterval: $indexationInterval,
-					issueServiceConfigurationIdentifier: $issueServiceConfigurationIdentifier
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":                             project,
-			"configuration":                       configuration,
-			"repository":                          repository,
-			"indexationInterval":                  indexation,
-			"issueServiceConfigurationIdentifier": issueService,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetProjectGitHubConfigurationProperty.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return client.SetProjectGitHubProperty(cfg, project, configuration, repository, indexation, issueService)
 	},
 }
 
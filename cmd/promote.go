@@ -35,6 +35,25 @@ var promoteCmd = &cobra.Command{
 	Long: `Promotes a build.
 	
 	ontrack-cli promote -p PROJECT -b BRANCH -n BUILD -l PROMOTION -d DESCRIPTION
+
+The build can also be given by reference, like '-n @latest' or '-n @commit:<sha>' - see 'ontrack-cli build show --help'.
+
+The state of the build can be checked before promoting it:
+
+	ontrack-cli promote -p PROJECT -b BRANCH -n BUILD -l PROMOTION \
+		--require-validation unit \
+		--require-validation perf:WARNING \
+		--require-promotion SILVER \
+		--require-auto-criteria
+
+where:
+
+* '--require-validation VS[:STATUS]' - the last run of the build for VS must have the STATUS status (PASSED by default)
+* '--require-promotion LEVEL' - the build must already be promoted to LEVEL
+* '--require-auto-criteria' - the build must meet the auto promotion criteria of the promotion level
+
+If the requirements are not met, the promotion is refused and the command exits with code 4,
+unless '--force' is set.
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project, err := cmd.Flags().GetString("project")
@@ -65,6 +84,17 @@ var promoteCmd = &cobra.Command{
 			return err
 		}
 
+		// Build reference
+		build, err = ResolveBuild(cfg, project, branch, build)
+		if err != nil {
+			return err
+		}
+
+		// Requirements
+		if err := CheckPromotionGuards(cmd, cfg, project, branch, build, promotion); err != nil {
+			return err
+		}
+
 		// Data
 		var data struct {
 			CreatePromotionRun struct {
@@ -129,7 +159,7 @@ func init() {
 	// promoteCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	promoteCmd.Flags().StringP("project", "p", "", "Name of the project")
 	promoteCmd.Flags().StringP("branch", "b", "", "Name of the branch")
-	promoteCmd.Flags().StringP("build", "n", "", "Name of the build")
+	promoteCmd.Flags().StringP("build", "n", "", buildRefUsage)
 	promoteCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level")
 	promoteCmd.Flags().StringP("description", "d", "", "Description for the promotion")
 
@@ -137,4 +167,7 @@ func init() {
 	promoteCmd.MarkFlagRequired("branch")
 	promoteCmd.MarkFlagRequired("build")
 	promoteCmd.MarkFlagRequired("promotion")
+
+	// Requirements
+	InitPromotionGuardCommandFlags(promoteCmd)
 }
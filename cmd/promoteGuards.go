@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+// InitPromotionGuardCommandFlags registers the flags checking the state of a build before promoting it
+func InitPromotionGuardCommandFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("require-validation", []string{}, "Validation stamp whose last run must have the given status (PASSED by default), like 'unit' or 'perf:WARNING' (can be repeated)")
+	cmd.Flags().StringSlice("require-promotion", []string{}, "Promotion level the build must already be promoted to (can be repeated)")
+	cmd.Flags().Bool("require-auto-criteria", false, "Requires the build to meet the auto promotion criteria of the promotion level")
+	cmd.Flags().Bool("force", false, "Promotes the build even if the requirements are not met")
+}
+
+// CheckPromotionGuards checks the requirements set on the command line against the build.
+// An error with the ExitCodeCriteriaNotMet exit code is returned if some requirements are not met,
+// unless the promotion is forced.
+func CheckPromotionGuards(
+	cmd *cobra.Command,
+	cfg *config.Config,
+	project string,
+	branch string,
+	build string,
+	promotion string,
+) error {
+	requiredValidations, err := cmd.Flags().GetStringSlice("require-validation")
+	if err != nil {
+		return err
+	}
+
+	requiredPromotions, err := cmd.Flags().GetStringSlice("require-promotion")
+	if err != nil {
+		return err
+	}
+
+	requireAutoCriteria, err := cmd.Flags().GetBool("require-auto-criteria")
+	if err != nil {
+		return err
+	}
+
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	// No requirement
+	if len(requiredValidations) == 0 && len(requiredPromotions) == 0 && !requireAutoCriteria {
+		return nil
+	}
+
+	// State of the build
+	state, err := getBuildPromotionState(cfg, project, branch, build)
+	if err != nil || state == nil {
+		return err
+	}
+
+	// Requirements
+	var criteria []promotionCriterion
+	for _, requirement := range requiredValidations {
+		validation, status := requirement, passedStatus
+		if index := strings.LastIndex(requirement, ":"); index > 0 {
+			validation, status = requirement[:index], strings.ToUpper(requirement[index+1:])
+		}
+		criteria = append(criteria, state.validationCriterion(validation, status, ""))
+	}
+	for _, required := range requiredPromotions {
+		criteria = append(criteria, state.promotionCriterion(required))
+	}
+	if requireAutoCriteria {
+		level, err := client.GetPromotionLevel(cfg, project, branch, promotion)
+		if err != nil {
+			return err
+		}
+		if level.AutoPromotion == nil {
+			return fmt.Errorf("promotion level %s has no auto promotion criteria", promotion)
+		}
+		validationStamps, err := client.GetValidationStampNames(cfg, project, branch)
+		if err != nil {
+			return err
+		}
+		autoCriteria, err := state.autoPromotionCriteria(level.AutoPromotion, validationStamps)
+		if err != nil {
+			return err
+		}
+		criteria = append(criteria, autoCriteria...)
+	}
+
+	// Checks
+	missing := 0
+	for _, criterion := range criteria {
+		if !criterion.Satisfied {
+			missing++
+		}
+	}
+	if missing == 0 {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "Build %s does not meet the requirements for %s:\n", build, promotion)
+	printPromotionCriteria(os.Stderr, criteria)
+	if force {
+		fmt.Fprintf(os.Stderr, "Promotion forced despite %d unmet requirement(s)\n", missing)
+		return nil
+	}
+
+	// The usage is not relevant for a refused promotion
+	cmd.SilenceUsage = true
+	return NewExitError(ExitCodeCriteriaNotMet, "Promotion of build %s to %s refused, %d requirement(s) not met (use --force to override)", build, promotion, missing)
+}
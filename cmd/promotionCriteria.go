@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+// Status expected by the auto promotion for a validation stamp
+const passedStatus = "PASSED"
+
+// promotionCriterion is a criterion for a build to be promoted, checked against its latest runs
+type promotionCriterion struct {
+	// "validation" or "promotion"
+	Kind string
+	// Name of the validation stamp or of the promotion level
+	Name string
+	// Expected status for a validation stamp
+	Expected string
+	// Status of the last validation run, empty if the build was not validated
+	Status string
+	// Why the criterion applies, when it does not come from an explicit list
+	Reason string
+	// Whether the build meets the criterion
+	Satisfied bool
+}
+
+// buildPromotionState is the state of a build against which promotion criteria are checked
+type buildPromotionState struct {
+	// Status of the last validation run, per validation stamp
+	Statuses map[string]string
+	// Names of the promotion levels the build is promoted to
+	Promotions []string
+}
+
+// getBuildPromotionState loads the latest validation statuses and the promotions of a build.
+// If the configuration is disabled, nil is returned.
+func getBuildPromotionState(cfg *config.Config, project string, branch string, build string) (*buildPromotionState, error) {
+	statuses, err := client.GetLastValidationStatuses(cfg, project, branch, build)
+	if err != nil || cfg.Disabled {
+		return nil, err
+	}
+	runs, err := client.GetBuildPromotionRuns(cfg, project, branch, build, "")
+	if err != nil {
+		return nil, err
+	}
+	state := &buildPromotionState{
+		Statuses: statuses,
+	}
+	for _, run := range runs {
+		if !slices.Contains(state.Promotions, run.PromotionLevel.Name) {
+			state.Promotions = append(state.Promotions, run.PromotionLevel.Name)
+		}
+	}
+	return state, nil
+}
+
+// validationCriterion checks the last status of the build for a validation stamp
+func (s *buildPromotionState) validationCriterion(validation string, expected string, reason string) promotionCriterion {
+	status := s.Statuses[validation]
+	return promotionCriterion{
+		Kind:      "validation",
+		Name:      validation,
+		Expected:  expected,
+		Status:    status,
+		Reason:    reason,
+		Satisfied: status == expected,
+	}
+}
+
+// promotionCriterion checks that the build is promoted to a level
+func (s *buildPromotionState) promotionCriterion(promotion string) promotionCriterion {
+	return promotionCriterion{
+		Kind:      "promotion",
+		Name:      promotion,
+		Satisfied: slices.Contains(s.Promotions, promotion),
+	}
+}
+
+// autoPromotionCriteria lists the criteria of an auto promotion: the explicit validation stamps, the
+// validation stamps of the branch selected by the include & exclude regular expressions, and the
+// promotion levels it depends on.
+func (s *buildPromotionState) autoPromotionCriteria(autoPromotion *client.AutoPromotion, validationStamps []string) ([]promotionCriterion, error) {
+	var criteria []promotionCriterion
+	for _, validation := range autoPromotion.ValidationStamps {
+		criteria = append(criteria, s.validationCriterion(validation, passedStatus, ""))
+	}
+	if autoPromotion.Include != "" {
+		include, err := regexp.Compile("^(?:" + autoPromotion.Include + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid include expression %q: %w", autoPromotion.Include, err)
+		}
+		var exclude *regexp.Regexp
+		if autoPromotion.Exclude != "" {
+			exclude, err = regexp.Compile("^(?:" + autoPromotion.Exclude + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude expression %q: %w", autoPromotion.Exclude, err)
+			}
+		}
+		for _, validation := range validationStamps {
+			if slices.Contains(autoPromotion.ValidationStamps, validation) || !include.MatchString(validation) {
+				continue
+			}
+			if exclude != nil && exclude.MatchString(validation) {
+				continue
+			}
+			criteria = append(criteria, s.validationCriterion(validation, passedStatus, fmt.Sprintf("included by %q", autoPromotion.Include)))
+		}
+	}
+	for _, promotion := range autoPromotion.PromotionLevels {
+		criteria = append(criteria, s.promotionCriterion(promotion))
+	}
+	return criteria, nil
+}
+
+// printPromotionCriteria prints a checklist of criteria and returns the number of unmet ones
+func printPromotionCriteria(w io.Writer, criteria []promotionCriterion) int {
+	missing := 0
+	for _, criterion := range criteria {
+		mark := "[x]"
+		if !criterion.Satisfied {
+			mark = "[ ]"
+			missing++
+		}
+		var detail string
+		switch {
+		case criterion.Kind == "promotion" && criterion.Satisfied:
+			detail = "promoted"
+		case criterion.Kind == "promotion":
+			detail = "not promoted"
+		case criterion.Status == "":
+			detail = fmt.Sprintf("not run, %s expected", criterion.Expected)
+		case criterion.Satisfied:
+			detail = criterion.Status
+		default:
+			detail = fmt.Sprintf("%s, %s expected", criterion.Status, criterion.Expected)
+		}
+		if criterion.Reason != "" {
+			detail += " - " + criterion.Reason
+		}
+		fmt.Fprintf(w, "  %s %s %s: %s\n", mark, criterion.Kind, criterion.Name, detail)
+	}
+	return missing
+}
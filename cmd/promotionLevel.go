@@ -22,6 +22,8 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
@@ -38,6 +40,40 @@ The simplest way to setup a promotion level for a branch:
 
 This will create a promotion level for the branch, or updates it if it exists already.
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The promotions file can be used without any branch
+		if cmd == promotionLevelGraphCmd {
+			fromYaml, err := cmd.Flags().GetString("from-yaml")
+			if err != nil {
+				return err
+			}
+			if fromYaml != "" {
+				return nil
+			}
+		}
+		// Checking the auto promotion file does not need any branch
+		if cmd == promotionLevelAutoCmd {
+			for _, name := range []string{"check", "schema"} {
+				offline, err := cmd.Flags().GetBool(name)
+				if err != nil {
+					return err
+				}
+				if offline {
+					return nil
+				}
+			}
+		}
+		for _, name := range []string{"project", "branch"} {
+			value, err := cmd.Flags().GetString(name)
+			if err != nil {
+				return err
+			}
+			if value == "" {
+				return fmt.Errorf(`required flag(s) "%s" not set`, name)
+			}
+		}
+		return nil
+	},
 	// Run: func(cmd *cobra.Command, args []string) {},
 }
 
@@ -52,9 +88,6 @@ func init() {
 	promotionLevelCmd.PersistentFlags().StringP("project", "p", "", "Name of the project")
 	promotionLevelCmd.PersistentFlags().StringP("branch", "b", "", "Name of the branch")
 
-	promotionLevelCmd.MarkPersistentFlagRequired("project")
-	promotionLevelCmd.MarkPersistentFlagRequired("branch")
-
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// promotionLevelCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
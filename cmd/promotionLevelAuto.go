@@ -1,50 +1,17 @@
 package cmd
 
 import (
-	"io"
+	"fmt"
 	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
+	"ontrack-cli/cmd/promotions"
 	config "ontrack-cli/config"
 	"os"
 	"slices"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v2"
 )
 
-type AutoPromotions struct {
-	// List of validations and their configuration
-	Validations []ValidationConfig
-	// List of promotions
-	Promotions []PromotionConfig
-}
-
-type ValidationConfig struct {
-	// Name of the validation
-	Name string
-	// Optional description for the validation
-	Description string
-	// Optional data type
-	DataType *string
-	// Optional data type config
-	DataTypeConfig *string
-	// Test configuration
-	Tests *TestSummaryValidationConfig
-}
-
-type TestSummaryValidationConfig struct {
-	// Warning if skipped tests
-	WarningIfSkipped bool
-}
-
-type PromotionConfig struct {
-	// Name of the promotion
-	Name string
-	// List of validations
-	Validations []string
-	// List of promotions
-	Promotions []string
-}
-
 var promotionLevelAutoCmd = &cobra.Command{
 	Use:   "auto",
 	Short: "Sets up promotions and their auto promotions criteria using local YAML file",
@@ -64,8 +31,28 @@ validations:
 	  description: Unit tests
 	  tests:
 		warningIfSkipped: false
+	- name: security
+	  chml:
+		warningLevel:
+		  level: HIGH
+		  value: 1
+		failedLevel:
+		  level: CRITICAL
+		  value: 1
+	- name: coverage
+	  percentage:
+		warningThreshold: 80
+		failureThreshold: 60
+		okIfGreater: true
+	- name: performance
+	  metrics: true
+	- name: ratio
+	  dataType: fraction
+	  dataTypeConfig: "{warningThreshold: 90, failureThreshold: 50, okIfGreater: true}"
+	- name: lint
 promotions:
 	- name: BRONZE
+	  description: Built and tested
 	  validations:
 		- unit-tests
 		- lint
@@ -74,62 +61,161 @@ promotions:
 		- BRONZE
 	  validations:
 		- deploy
+	  include: "integration-.*"
+	  exclude: "integration-slow-.*"
+
+Each validation may have at most one type: 'tests', 'chml', 'percentage', 'metrics' or a generic
+'dataType' (FQCN or alias, see 'ontrack-cli vs data-types') with its optional 'dataTypeConfig'.
+The validations used by the promotions but not listed are created without any type.
+
+The file is checked before being applied: unknown fields, duplicates, references to promotions
+which are not defined and dependency cycles between promotions are reported with their position
+in the file. Use '--check' to only check the file, for example in a pre-commit hook:
+
+	ontrack-cli pl auto --check [--yaml .ontrack/promotions.yaml] [-p PROJECT] [-b BRANCH]
+
+The project is needed only to read the extended files stored in its properties, and the branch
+only to check the file with its overrides.
+
+A file can extend one or several base files, for example to share the same definitions between
+repositories. The base files are either local paths, relative to the file extending them, or the
+value of an item of the meta information property of the project, prefixed by 'property:':
+
+extends:
+	- ../common/promotions.yaml
+	- property:promotions
+
+The files are merged in order, the file itself coming last: a validation or a promotion replaces
+the one with the same name defined before, at the same position, and the other ones are appended.
+
+The validations and promotions can be overridden for the branches whose name fully matches a regular
+expression, the matching overrides being merged in order, the same way:
+
+branches:
+	- branch: "release/.*"
+	  promotions:
+		- name: GOLD
+		  validations:
+			- unit-tests
+			- security
+
+Environment variables can be used anywhere in the files, as '${NAME}' or '${NAME:-default}'. An
+undefined variable without any default value is an error. Use '$$' for a literal '$'.
+
+The JSON Schema of the file, to be used by editors, is printed by:
+
+	ontrack-cli pl auto --schema
+
+The changes can be previewed, without applying them, using:
+
+	ontrack-cli pl auto -p PROJECT -b BRANCH --dry-run
+
+The validation stamps and promotion levels of the branch which are not declared in the file are kept,
+unless '--prune' is set. They are then deleted, after a confirmation which can be skipped using '--yes'. When
+the deletions are not confirmed, nothing is applied and the command fails.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		// Parameters
-		project, err := cmd.Flags().GetString("project")
+		promotionYamlPath, err := cmd.Flags().GetString("yaml")
 		if err != nil {
 			return err
 		}
-		branch, err := cmd.Flags().GetString("branch")
+		if promotionYamlPath == "" {
+			promotionYamlPath = ".ontrack/promotions.yaml"
+		}
+		check, err := cmd.Flags().GetBool("check")
 		if err != nil {
 			return err
 		}
-		branch = NormalizeBranchName(branch)
-		promotionYamlPath, err := cmd.Flags().GetString("yaml")
+		schema, err := cmd.Flags().GetBool("schema")
 		if err != nil {
 			return err
 		}
-		if promotionYamlPath == "" {
-			promotionYamlPath = ".ontrack/promotions.yaml"
+
+		// JSON Schema only
+		if schema {
+			fmt.Print(promotions.JSONSchema)
+			return nil
 		}
 
-		// Configuration
-		cfg, err := config.GetSelectedConfiguration()
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+		rawBranch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch := NormalizeBranchName(rawBranch)
+
+		// Reading & checking the promotions.yaml file
+		root, err := loadPromotionsFile(promotionYamlPath, project, rawBranch)
 		if err != nil {
+			// The usage is not relevant for an invalid file
+			cmd.SilenceUsage = true
 			return err
 		}
+		if check {
+			fmt.Printf("%s is valid\n", promotionYamlPath)
+			return nil
+		}
 
-		// Reading the promotions.yaml file
-		var root AutoPromotions
-		reader, err := os.Open(promotionYamlPath)
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+		prune, err := cmd.Flags().GetBool("prune")
 		if err != nil {
 			return err
 		}
-		buf, err := io.ReadAll(reader)
+
+		// Configuration
+		cfg, err := config.GetSelectedConfiguration()
 		if err != nil {
 			return err
 		}
-		yaml.Unmarshal(buf, &root)
+
+		// Plan against the current validation stamps & promotion levels
+		var changes []promotions.Change
+		if dryRun || prune {
+			stamps, err := client.GetValidationStamps(cfg, project, branch, "")
+			if err != nil {
+				return err
+			}
+			var levels []client.PromotionLevel
+			branchLevels, err := client.GetPromotionLevels(cfg, project, branch)
+			if err != nil {
+				return err
+			}
+			if branchLevels != nil {
+				levels = branchLevels.PromotionLevels
+			}
+			changes, err = promotions.Plan(root, stamps, levels, prune)
+			if err != nil {
+				return err
+			}
+			promotions.PrintPlan(os.Stdout, changes)
+			if dryRun {
+				return nil
+			}
+			if deletions := promotions.Count(changes, promotions.ActionDelete); deletions > 0 {
+				confirmed, err := Confirm(cmd, fmt.Sprintf("Delete %d validation stamp(s) and promotion level(s) not declared in %s?", deletions, promotionYamlPath))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					return ErrCancelled
+				}
+			}
+		}
 
 		// Setup of validations
 		var createdValidations []string
 		for _, validation := range root.Validations {
 			createdValidations = append(createdValidations, validation.Name)
-			// TODO Generic data type
-			// Tests data type
-			if validation.Tests != nil {
-				err = SetupTestValidationStamp(
-					project,
-					branch,
-					validation.Name,
-					validation.Description,
-					validation.Tests.WarningIfSkipped,
-				)
-				if err != nil {
-					return err
-				}
+			if err := setupAutoValidation(cfg, project, branch, validation); err != nil {
+				return err
 			}
 		}
 
@@ -165,30 +251,150 @@ promotions:
 
 		// Auto promotion setup
 		for _, promotion := range root.Promotions {
-			// Setup the promotion level
-			err := client.SetupPromotionLevel(
-				cfg,
-				project,
-				branch,
-				promotion.Name,
-				"",
-				len(promotion.Validations) > 0 || len(promotion.Promotions) > 0,
-				promotion.Validations,
-				promotion.Promotions,
-				"",
-				"",
-			)
-			if err != nil {
+			if err := setupAutoPromotion(cfg, project, branch, promotion); err != nil {
 				return err
 			}
 		}
 
+		// Pruning, promotion levels first
+		for _, kind := range []string{promotions.KindPromotion, promotions.KindValidation} {
+			for _, change := range changes {
+				if change.Action != promotions.ActionDelete || change.Kind != kind {
+					continue
+				}
+				if kind == promotions.KindPromotion {
+					err = client.DeletePromotionLevel(cfg, change.ID)
+				} else {
+					err = client.DeleteValidationStamp(cfg, change.ID)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+
 		// OK
 		return nil
 	},
 }
 
+// loadPromotionsFile reads a promotions file, with the overrides for the given branch if any.
+// The files stored in the meta information of the project can be extended only if a project is given.
+func loadPromotionsFile(path string, project string, branch string) (*promotions.Config, error) {
+	var resolver promotions.Resolver
+	if project != "" {
+		resolver = func(name string) ([]byte, error) {
+			cfg, err := config.GetSelectedConfiguration()
+			if err != nil {
+				return nil, err
+			}
+			value, err := client.GetProjectMetaInfo(cfg, project, name)
+			return []byte(value), err
+		}
+	}
+
+	// Branch overrides, using both the Git & Ontrack branch names
+	var branches []string
+	if branch != "" {
+		branches = []string{branch, NormalizeBranchName(branch)}
+	}
+
+	return promotions.Load(path, branches, resolver)
+}
+
+// setupAutoPromotion sets up a promotion level, with its auto promotion when some criteria are defined
+func setupAutoPromotion(cfg *config.Config, project string, branch string, promotion promotions.Promotion) error {
+	return client.SetupPromotionLevel(
+		cfg,
+		project,
+		branch,
+		promotion.Name,
+		promotion.Description,
+		len(promotion.Validations) > 0 || len(promotion.Promotions) > 0 || promotion.Include != "",
+		promotion.Validations,
+		promotion.Promotions,
+		promotion.Include,
+		promotion.Exclude,
+	)
+}
+
+// setupAutoValidation sets up a validation stamp according to its type
+func setupAutoValidation(cfg *config.Config, project string, branch string, validation promotions.Validation) error {
+	switch {
+	case validation.Tests != nil:
+		return SetupTestValidationStamp(
+			project,
+			branch,
+			validation.Name,
+			validation.Description,
+			validation.Tests.WarningIfSkipped,
+		)
+	case validation.CHML != nil:
+		return SetupCHMLValidationStamp(
+			project,
+			branch,
+			validation.Name,
+			validation.Description,
+			validation.CHML.WarningLevel.Level,
+			validation.CHML.WarningLevel.Value,
+			validation.CHML.FailedLevel.Level,
+			validation.CHML.FailedLevel.Value,
+		)
+	case validation.Percentage != nil:
+		return SetupPercentageValidationStamp(
+			project,
+			branch,
+			validation.Name,
+			validation.Description,
+			validation.Percentage.WarningThreshold,
+			validation.Percentage.FailureThreshold,
+			validation.Percentage.OkIfGreater,
+		)
+	case validation.Metrics:
+		return SetupMetricsValidationStamp(
+			project,
+			branch,
+			validation.Name,
+			validation.Description,
+		)
+	case validation.DataType != nil:
+		dataType := datatypes.Resolve(*validation.DataType)
+		dataTypeConfig := ""
+		if validation.DataTypeConfig != nil {
+			value, err := datatypes.ValidateConfig(dataType, *validation.DataTypeConfig)
+			if err != nil {
+				return fmt.Errorf("validation %s: %w", validation.Name, err)
+			}
+			dataTypeConfig = datatypes.GraphQLLiteral(value)
+		}
+		return client.SetupValidationStamp(
+			cfg,
+			project,
+			branch,
+			validation.Name,
+			validation.Description,
+			dataType,
+			dataTypeConfig,
+		)
+	default:
+		return client.SetupValidationStamp(
+			cfg,
+			project,
+			branch,
+			validation.Name,
+			validation.Description,
+			"",
+			"",
+		)
+	}
+}
+
 func init() {
 	promotionLevelCmd.AddCommand(promotionLevelAutoCmd)
 	promotionLevelAutoCmd.Flags().StringP("yaml", "y", ".ontrack/promotions.yaml", "Path to the YAML file")
+	promotionLevelAutoCmd.Flags().Bool("check", false, "Only checks the YAML file, without any connection to Ontrack")
+	promotionLevelAutoCmd.Flags().Bool("schema", false, "Prints the JSON Schema of the YAML file")
+	promotionLevelAutoCmd.Flags().Bool("dry-run", false, "Prints the changes to apply to the branch, without applying them")
+	promotionLevelAutoCmd.Flags().Bool("prune", false, "Deletes the validation stamps and promotion levels not declared in the YAML file")
+	InitConfirmCommandFlags(promotionLevelAutoCmd)
 }
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var promotionLevelDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes a promotion level",
+	Long: `Deletes a promotion level, together with all its promotion runs.
+
+	ontrack-cli pl delete --project PROJECT --branch BRANCH --promotion PROMOTION
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		promotion, err := cmd.Flags().GetString("promotion")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		level, err := client.GetPromotionLevel(cfg, project, branch, promotion)
+		if err != nil || level == nil {
+			return err
+		}
+
+		confirmed, err := Confirm(cmd, fmt.Sprintf("Delete the promotion level %s of %s/%s, together with all its runs?", level.Name, project, branch))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return ErrCancelled
+		}
+
+		return client.DeletePromotionLevel(cfg, level.ID)
+	},
+}
+
+func init() {
+	promotionLevelCmd.AddCommand(promotionLevelDeleteCmd)
+
+	promotionLevelDeleteCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level")
+	InitConfirmCommandFlags(promotionLevelDeleteCmd)
+	promotionLevelDeleteCmd.MarkFlagRequired("promotion")
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/promotions"
+	config "ontrack-cli/config"
+)
+
+// Formats of the graph
+const (
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+	GraphJSON    = "json"
+)
+
+var promotionLevelGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Prints the validation stamps and promotion levels of a branch as a graph",
+	Long: `Prints the validation stamps and promotion levels of a branch as a graph.
+
+	ontrack-cli pl graph -p PROJECT -b BRANCH [--format dot|mermaid|json]
+
+The validation stamps and the promotion levels are the nodes. The edges are the auto promotion criteria:
+the validation stamps needed by a promotion level, the ones included by its 'include' and 'exclude'
+expressions (dashed) and the promotion levels it depends on (bold).
+
+By default, the graph is the current state of the branch in Ontrack. Use '--from-yaml' to get it from the
+file used by 'ontrack-cli pl auto', without any connection to Ontrack unless it extends a project property:
+
+	ontrack-cli pl graph --from-yaml .ontrack/promotions.yaml [-p PROJECT] [-b BRANCH] --format mermaid
+
+The graph can be rendered using Graphviz, for example:
+
+	ontrack-cli pl graph -p PROJECT -b BRANCH | dot -Tsvg > promotions.svg
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		fromYaml, err := cmd.Flags().GetString("from-yaml")
+		if err != nil {
+			return err
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != GraphDOT && format != GraphMermaid && format != GraphJSON {
+			return fmt.Errorf("unsupported graph format %q, must be one of %s, %s or %s", format, GraphDOT, GraphMermaid, GraphJSON)
+		}
+
+		var graph *promotions.Graph
+		title := fmt.Sprintf("%s/%s", project, branch)
+		if fromYaml != "" {
+			// From the YAML file
+			root, err := loadPromotionsFile(fromYaml, project, branch)
+			if err != nil {
+				// The usage is not relevant for an invalid file
+				cmd.SilenceUsage = true
+				return err
+			}
+			graph, err = root.Graph()
+			if err != nil {
+				return err
+			}
+			if project == "" {
+				title = fromYaml
+			}
+		} else {
+			// From the branch
+			branch = NormalizeBranchName(branch)
+			title = fmt.Sprintf("%s/%s", project, branch)
+
+			cfg, err := config.GetSelectedConfiguration()
+			if err != nil {
+				return err
+			}
+
+			stamps, err := client.GetValidationStamps(cfg, project, branch, "")
+			if err != nil {
+				return err
+			}
+			var validations []string
+			for _, stamp := range stamps {
+				validations = append(validations, stamp.Name)
+			}
+			var levels []client.PromotionLevel
+			branchLevels, err := client.GetPromotionLevels(cfg, project, branch)
+			if err != nil {
+				return err
+			}
+			if branchLevels != nil {
+				levels = branchLevels.PromotionLevels
+			}
+			graph, err = promotions.NewGraph(validations, levels)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch format {
+		case GraphMermaid:
+			graph.WriteMermaid(os.Stdout)
+		case GraphJSON:
+			buf, err := json.MarshalIndent(graph, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(buf))
+		default:
+			graph.WriteDOT(os.Stdout, title)
+		}
+
+		// OK
+		return nil
+	},
+}
+
+func init() {
+	promotionLevelCmd.AddCommand(promotionLevelGraphCmd)
+	promotionLevelGraphCmd.Flags().String("from-yaml", "", "Path to the YAML file used by 'pl auto', instead of the branch in Ontrack")
+	promotionLevelGraphCmd.Flags().String("format", GraphDOT, "Format of the graph: dot, mermaid or json")
+}
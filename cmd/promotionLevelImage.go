@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var promotionLevelImageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Sets the image of a promotion level",
+	Long: `Uploads the image of a promotion level.
+
+	ontrack-cli pl image --project PROJECT --branch BRANCH --promotion PROMOTION --file icon.png
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		promotion, err := cmd.Flags().GetString("promotion")
+		if err != nil {
+			return err
+		}
+
+		file, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		level, err := client.GetPromotionLevel(cfg, project, branch, promotion)
+		if err != nil || level == nil {
+			return err
+		}
+
+		return client.SetPromotionLevelImage(cfg, level.ID, file)
+	},
+}
+
+func init() {
+	promotionLevelCmd.AddCommand(promotionLevelImageCmd)
+
+	promotionLevelImageCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level")
+	promotionLevelImageCmd.Flags().StringP("file", "f", "", "Path to the image file (PNG)")
+	promotionLevelImageCmd.MarkFlagRequired("promotion")
+	promotionLevelImageCmd.MarkFlagRequired("file")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var promotionLevelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the promotion levels of a branch",
+	Long: `Lists the promotion levels of a branch, in their order, with their auto-promotion criteria.
+
+	ontrack-cli pl list --project PROJECT --branch BRANCH
+
+Use '--output json' or '--output yaml' to get the raw data.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		levels, err := client.GetPromotionLevels(cfg, project, branch)
+		if err != nil {
+			return err
+		}
+		var result []client.PromotionLevel
+		if levels != nil {
+			result = levels.PromotionLevels
+		}
+
+		return PrintOutput(cmd, result, func(w io.Writer) error {
+			fmt.Fprintln(w, "ID\tNAME\tAUTO PROMOTION")
+			for _, level := range result {
+				fmt.Fprintf(w, "%d\t%s\t%s\n",
+					level.ID,
+					level.Name,
+					formatAutoPromotion(level.AutoPromotion),
+				)
+			}
+			return nil
+		})
+	},
+}
+
+// formatAutoPromotion returns a one-line description of auto-promotion criteria
+func formatAutoPromotion(autoPromotion *client.AutoPromotion) string {
+	if autoPromotion == nil {
+		return ""
+	}
+	var items []string
+	if len(autoPromotion.ValidationStamps) > 0 {
+		items = append(items, "validations: "+strings.Join(autoPromotion.ValidationStamps, ", "))
+	}
+	if autoPromotion.Include != "" {
+		items = append(items, "include: "+autoPromotion.Include)
+	}
+	if autoPromotion.Exclude != "" {
+		items = append(items, "exclude: "+autoPromotion.Exclude)
+	}
+	if len(autoPromotion.PromotionLevels) > 0 {
+		items = append(items, "depends on: "+strings.Join(autoPromotion.PromotionLevels, ", "))
+	}
+	return strings.Join(items, "; ")
+}
+
+func init() {
+	promotionLevelCmd.AddCommand(promotionLevelListCmd)
+	InitOutputCommandFlags(promotionLevelListCmd)
+}
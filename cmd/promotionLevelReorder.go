@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var promotionLevelReorderCmd = &cobra.Command{
+	Use:   "reorder",
+	Short: "Sets the order of the promotion levels of a branch",
+	Long: `Sets the order of the promotion levels of a branch.
+
+	ontrack-cli pl reorder --project PROJECT --branch BRANCH --order BRONZE,SILVER,GOLD
+
+The promotion levels which are not listed keep their relative order, after the listed ones.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		order, err := cmd.Flags().GetStringSlice("order")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		levels, err := client.GetPromotionLevels(cfg, project, branch)
+		if err != nil || levels == nil {
+			return err
+		}
+
+		// Listed levels first
+		var ids []int
+		listed := make(map[string]bool)
+		for _, name := range order {
+			if listed[name] {
+				return fmt.Errorf("promotion level %s is listed several times", name)
+			}
+			var found bool
+			for _, level := range levels.PromotionLevels {
+				if level.Name == name {
+					ids = append(ids, level.ID)
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("promotion level %s not found in %s/%s", name, project, branch)
+			}
+			listed[name] = true
+		}
+		// Then the others
+		for _, level := range levels.PromotionLevels {
+			if !listed[level.Name] {
+				ids = append(ids, level.ID)
+			}
+		}
+
+		return client.ReorderPromotionLevels(cfg, levels.BranchID, ids)
+	},
+}
+
+func init() {
+	promotionLevelCmd.AddCommand(promotionLevelReorderCmd)
+
+	promotionLevelReorderCmd.Flags().StringSlice("order", []string{}, "Names of the promotion levels, in their new order")
+	promotionLevelReorderCmd.MarkFlagRequired("order")
+}
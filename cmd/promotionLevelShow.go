@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var promotionLevelShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Displays a promotion level",
+	Long: `Displays a promotion level, with its auto-promotion criteria.
+
+	ontrack-cli pl show --project PROJECT --branch BRANCH --promotion PROMOTION
+
+Use '--output json' or '--output yaml' to get the raw data.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		promotion, err := cmd.Flags().GetString("promotion")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		level, err := client.GetPromotionLevel(cfg, project, branch, promotion)
+		if err != nil || level == nil {
+			return err
+		}
+
+		return PrintOutput(cmd, level, func(w io.Writer) error {
+			fmt.Fprintf(w, "ID:\t%d\n", level.ID)
+			fmt.Fprintf(w, "Name:\t%s\n", level.Name)
+			if level.Description != "" {
+				fmt.Fprintf(w, "Description:\t%s\n", level.Description)
+			}
+			fmt.Fprintf(w, "Image:\t%t\n", level.Image)
+			if level.AutoPromotion != nil {
+				fmt.Fprintln(w, "Auto promotion:")
+				fmt.Fprintf(w, "  Validations:\t%s\n", strings.Join(level.AutoPromotion.ValidationStamps, ", "))
+				fmt.Fprintf(w, "  Include:\t%s\n", level.AutoPromotion.Include)
+				fmt.Fprintf(w, "  Exclude:\t%s\n", level.AutoPromotion.Exclude)
+				fmt.Fprintf(w, "  Depends on:\t%s\n", strings.Join(level.AutoPromotion.PromotionLevels, ", "))
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	promotionLevelCmd.AddCommand(promotionLevelShowCmd)
+
+	promotionLevelShowCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level")
+	promotionLevelShowCmd.MarkFlagRequired("promotion")
+
+	InitOutputCommandFlags(promotionLevelShowCmd)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var promotionRunCmd = &cobra.Command{
+	Use:     "promotion-run",
+	Aliases: []string{"pr"},
+	Short:   "Management of promotion runs",
+	Long: `Management of promotion runs.
+
+To list the promotion runs of a build:
+
+    ontrack-cli promotion-run list -p PROJECT -b BRANCH -n BUILD
+
+To list the last promotion runs for a promotion level:
+
+    ontrack-cli promotion-run list -p PROJECT -b BRANCH -l PROMOTION
+
+To delete a promotion run:
+
+    ontrack-cli promotion-run delete --id ID
+`,
+}
+
+func init() {
+	rootCmd.AddCommand(promotionRunCmd)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var promotionRunDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes a promotion run",
+	Long: `Deletes a promotion run, to revoke the promotion of a build.
+
+The promotion run can be identified by its ID:
+
+	ontrack-cli promotion-run delete --id ID
+
+or by its build and promotion level, in which case the latest run is deleted:
+
+	ontrack-cli promotion-run delete -p PROJECT -b BRANCH -n BUILD -l PROMOTION
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := cmd.Flags().GetInt("id")
+		if err != nil {
+			return err
+		}
+
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		promotion, err := cmd.Flags().GetString("promotion")
+		if err != nil {
+			return err
+		}
+
+		if id == 0 && (project == "" || branch == "" || build == "" || promotion == "") {
+			return errors.New("Either --id or all of --project, --branch, --build and --promotion are required")
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Build reference
+		build, err = ResolveBuild(cfg, project, branch, build)
+		if err != nil {
+			return err
+		}
+
+		// Latest run for the build & promotion level
+		message := fmt.Sprintf("Delete the promotion run %d?", id)
+		if id == 0 {
+			runs, err := client.GetBuildPromotionRuns(cfg, project, branch, build, promotion)
+			if err != nil {
+				return err
+			}
+			if len(runs) == 0 {
+				if cfg.Disabled {
+					return nil
+				}
+				return fmt.Errorf("Build %s is not promoted to %s", build, promotion)
+			}
+			latest := runs[0]
+			for _, run := range runs {
+				if run.ID > latest.ID {
+					latest = run
+				}
+			}
+			id = latest.ID
+			message = fmt.Sprintf(
+				"Delete the promotion run %d of build %s to %s (%s by %s)?",
+				latest.ID,
+				latest.Build.Name,
+				latest.PromotionLevel.Name,
+				latest.Creation.Time,
+				latest.Creation.User,
+			)
+		}
+
+		confirmed, err := Confirm(cmd, message)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return ErrCancelled
+		}
+
+		return client.DeletePromotionRun(cfg, id)
+	},
+}
+
+func init() {
+	promotionRunCmd.AddCommand(promotionRunDeleteCmd)
+
+	promotionRunDeleteCmd.Flags().Int("id", 0, "ID of the promotion run")
+	promotionRunDeleteCmd.Flags().StringP("project", "p", "", "Name of the project")
+	promotionRunDeleteCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+	promotionRunDeleteCmd.Flags().StringP("build", "n", "", buildRefUsage)
+	promotionRunDeleteCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level")
+
+	InitConfirmCommandFlags(promotionRunDeleteCmd)
+}
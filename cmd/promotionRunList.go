@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var promotionRunListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists promotion runs",
+	Long: `Lists the promotion runs of a build, optionally restricted to a promotion level:
+
+	ontrack-cli promotion-run list -p PROJECT -b BRANCH -n BUILD [-l PROMOTION]
+
+or the last promotion runs for a promotion level:
+
+	ontrack-cli promotion-run list -p PROJECT -b BRANCH -l PROMOTION [--count 50]
+
+Use '--output json' or '--output yaml' to get the raw data.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		promotion, err := cmd.Flags().GetString("promotion")
+		if err != nil {
+			return err
+		}
+
+		count, err := cmd.Flags().GetInt("count")
+		if err != nil {
+			return err
+		}
+
+		if build == "" && promotion == "" {
+			return errors.New("Either --build or --promotion is required")
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Build reference
+		build, err = ResolveBuild(cfg, project, branch, build)
+		if err != nil {
+			return err
+		}
+
+		var runs []client.PromotionRun
+		if build != "" {
+			runs, err = client.GetBuildPromotionRuns(cfg, project, branch, build, promotion)
+		} else {
+			runs, err = client.GetPromotionLevelRuns(cfg, project, branch, promotion, count)
+		}
+		if err != nil {
+			return err
+		}
+		if runs == nil {
+			runs = []client.PromotionRun{}
+		}
+
+		return PrintOutput(cmd, runs, func(w io.Writer) error {
+			fmt.Fprintln(w, "ID\tBUILD\tPROMOTION\tCREATION\tAUTHOR\tDESCRIPTION")
+			for _, run := range runs {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+					run.ID,
+					run.Build.Name,
+					run.PromotionLevel.Name,
+					run.Creation.Time,
+					run.Creation.User,
+					run.Description,
+				)
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	promotionRunCmd.AddCommand(promotionRunListCmd)
+
+	promotionRunListCmd.Flags().StringP("project", "p", "", "Name of the project")
+	promotionRunListCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+	promotionRunListCmd.Flags().StringP("build", "n", "", buildRefUsage)
+	promotionRunListCmd.Flags().StringP("promotion", "l", "", "Name of the promotion level")
+	promotionRunListCmd.Flags().Int("count", 50, "Maximum number of promotion runs to return for a promotion level")
+
+	promotionRunListCmd.MarkFlagRequired("project")
+	promotionRunListCmd.MarkFlagRequired("branch")
+
+	InitOutputCommandFlags(promotionRunListCmd)
+}
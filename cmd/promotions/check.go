@@ -0,0 +1,292 @@
+package promotions
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"ontrack-cli/cmd/datatypes"
+)
+
+// Levels of the CHML thresholds
+var chmlLevels = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// check returns the problems of a decoded file: missing names, duplicates, invalid configurations,
+// references to undefined promotions and dependency cycles between promotions.
+func (c *Config) check(locate *locator) []Problem {
+	problems := c.duplicates(locate)
+	report := func(line int, column int, format string, args ...interface{}) {
+		problems = append(problems, Problem{Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(c.Validations) == 0 && len(c.Promotions) == 0 {
+		report(0, 0, "no validations nor promotions are defined")
+	}
+
+	// Validations
+	for _, validation := range c.Validations {
+		if validation.Name == "" {
+			report(0, 0, "a validation has no name")
+			continue
+		}
+		line, column := locate.entry("validations", validation.Name, 1)
+		for _, message := range validation.check() {
+			report(line, column, "validation %s: %s", validation.Name, message)
+		}
+	}
+
+	// Promotions
+	promotions := make(map[string]bool)
+	for _, promotion := range c.Promotions {
+		promotions[promotion.Name] = true
+	}
+	for _, promotion := range c.Promotions {
+		if promotion.Name == "" {
+			report(0, 0, "a promotion has no name")
+			continue
+		}
+		line, column := locate.entry("promotions", promotion.Name, 1)
+		for _, dependency := range promotion.Promotions {
+			if !promotions[dependency] {
+				refLine, refColumn := locate.reference(line, dependency)
+				report(refLine, refColumn, "promotion %s depends on the undefined promotion %s", promotion.Name, dependency)
+			}
+		}
+		if duplicate := firstDuplicate(promotion.Validations); duplicate != "" {
+			refLine, refColumn := locate.reference(line, duplicate)
+			report(refLine, refColumn, "promotion %s lists the validation %s several times", promotion.Name, duplicate)
+		}
+		if duplicate := firstDuplicate(promotion.Promotions); duplicate != "" {
+			refLine, refColumn := locate.reference(line, duplicate)
+			report(refLine, refColumn, "promotion %s lists the promotion %s several times", promotion.Name, duplicate)
+		}
+		for _, expression := range []string{promotion.Include, promotion.Exclude} {
+			if expression != "" {
+				if _, err := regexp.Compile(expression); err != nil {
+					refLine, refColumn := locate.reference(line, expression)
+					report(refLine, refColumn, "promotion %s: invalid regular expression %q", promotion.Name, expression)
+				}
+			}
+		}
+		if promotion.Exclude != "" && promotion.Include == "" {
+			report(line, column, "promotion %s: exclude is set without include", promotion.Name)
+		}
+	}
+
+	// Cycles
+	for _, cycle := range c.cycles() {
+		line, column := locate.entry("promotions", cycle[0], 1)
+		report(line, column, "dependency cycle between promotions: %s", strings.Join(cycle, " -> "))
+	}
+
+	// In the order of the file
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems
+}
+
+// Check returns the problems of a configuration which is not read from its own file,
+// like the one of a branch in a manifest, without any position
+func (c *Config) Check() []Problem {
+	return c.check(newLocator(""))
+}
+
+// duplicates returns the validations and promotions defined several times in a file
+func (c *Config) duplicates(locate *locator) []Problem {
+	var problems []Problem
+	validations := make(map[string]int)
+	for _, validation := range c.Validations {
+		validations[validation.Name]++
+		if validation.Name != "" && validations[validation.Name] == 2 {
+			line, column := locate.entry("validations", validation.Name, 2)
+			problems = append(problems, Problem{Line: line, Column: column, Message: fmt.Sprintf("validation %s is defined several times", validation.Name)})
+		}
+	}
+	promotions := make(map[string]int)
+	for _, promotion := range c.Promotions {
+		promotions[promotion.Name]++
+		if promotion.Name != "" && promotions[promotion.Name] == 2 {
+			line, column := locate.entry("promotions", promotion.Name, 2)
+			problems = append(problems, Problem{Line: line, Column: column, Message: fmt.Sprintf("promotion %s is defined several times", promotion.Name)})
+		}
+	}
+	return problems
+}
+
+// check returns the problems of the configuration of a validation
+func (v *Validation) check() []string {
+	var types []string
+	if v.Tests != nil {
+		types = append(types, "tests")
+	}
+	if v.CHML != nil {
+		types = append(types, "chml")
+	}
+	if v.Percentage != nil {
+		types = append(types, "percentage")
+	}
+	if v.Metrics {
+		types = append(types, "metrics")
+	}
+	if v.DataType != nil {
+		types = append(types, "dataType")
+	}
+	if len(types) > 1 {
+		return []string{fmt.Sprintf("only one type can be set, found %s", strings.Join(types, ", "))}
+	}
+
+	var messages []string
+	if v.DataTypeConfig != nil && v.DataType == nil {
+		messages = append(messages, "dataTypeConfig is set without dataType")
+	}
+	if v.DataType != nil && v.DataTypeConfig != nil {
+		if _, err := datatypes.ValidateConfig(*v.DataType, *v.DataTypeConfig); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if v.CHML != nil {
+		if !slices.Contains(chmlLevels, v.CHML.WarningLevel.Level) {
+			messages = append(messages, fmt.Sprintf("chml.warningLevel.level must be one of %s", strings.Join(chmlLevels, ", ")))
+		}
+		if !slices.Contains(chmlLevels, v.CHML.FailedLevel.Level) {
+			messages = append(messages, fmt.Sprintf("chml.failedLevel.level must be one of %s", strings.Join(chmlLevels, ", ")))
+		}
+	}
+	if v.Percentage != nil {
+		if outOfRange(v.Percentage.WarningThreshold) {
+			messages = append(messages, "percentage.warningThreshold must be between 0 and 100")
+		}
+		if outOfRange(v.Percentage.FailureThreshold) {
+			messages = append(messages, "percentage.failureThreshold must be between 0 and 100")
+		}
+	}
+	return messages
+}
+
+// cycles returns the dependency cycles between the promotions, each cycle starting
+// with the promotion defined first in the file.
+func (c *Config) cycles() [][]string {
+	dependencies := make(map[string][]string)
+	var names []string
+	for _, promotion := range c.Promotions {
+		if _, ok := dependencies[promotion.Name]; !ok {
+			names = append(names, promotion.Name)
+		}
+		dependencies[promotion.Name] = append(dependencies[promotion.Name], promotion.Promotions...)
+	}
+
+	var cycles [][]string
+	reported := make(map[string]bool)
+	done := make(map[string]bool)
+	var path []string
+	var visit func(name string)
+	visit = func(name string) {
+		if index := slices.Index(path, name); index >= 0 {
+			cycle := append(slices.Clone(path[index:]), name)
+			// Same cycle reached from another promotion
+			first := 0
+			for i, item := range cycle[:len(cycle)-1] {
+				if slices.Index(names, item) < slices.Index(names, cycle[first]) {
+					first = i
+				}
+			}
+			normalized := append(slices.Clone(cycle[first:len(cycle)-1]), cycle[:first+1]...)
+			key := strings.Join(normalized, "->")
+			if !reported[key] {
+				reported[key] = true
+				cycles = append(cycles, normalized)
+			}
+			return
+		}
+		if done[name] {
+			return
+		}
+		path = append(path, name)
+		for _, dependency := range dependencies[name] {
+			if _, defined := dependencies[dependency]; defined {
+				visit(dependency)
+			}
+		}
+		path = path[:len(path)-1]
+		done[name] = true
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return cycles
+}
+
+func firstDuplicate(items []string) string {
+	for index, item := range items {
+		if slices.Contains(items[:index], item) {
+			return item
+		}
+	}
+	return ""
+}
+
+func outOfRange(percentage *int) bool {
+	return percentage != nil && (*percentage < 0 || *percentage > 100)
+}
+
+// locator finds the position of the entries in the source of a file,
+// since the YAML decoder does not keep track of them.
+type locator struct {
+	lines []string
+}
+
+func newLocator(content string) *locator {
+	return &locator{lines: strings.Split(content, "\n")}
+}
+
+// entry returns the position of the name of the nth entry having this name in a top-level section,
+// or 0, 0 if not found.
+func (l *locator) entry(section string, name string, occurrence int) (int, int) {
+	if name == "" {
+		return 0, 0
+	}
+	sectionPattern := regexp.MustCompile(`^` + regexp.QuoteMeta(section) + `\s*:`)
+	namePattern := regexp.MustCompile(`^(\s*-?\s*name\s*:\s*["']?)` + regexp.QuoteMeta(name) + `["']?\s*(#.*)?$`)
+	inSection := false
+	for index, line := range l.lines {
+		switch {
+		case sectionPattern.MatchString(line):
+			inSection = true
+		case inSection && line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "#"):
+			inSection = false
+		case inSection:
+			if match := namePattern.FindStringSubmatch(line); match != nil {
+				occurrence--
+				if occurrence == 0 {
+					return index + 1, len(match[1]) + 1
+				}
+			}
+		}
+	}
+	return 0, 0
+}
+
+// reference returns the position of the first occurrence of a value after the line of an entry,
+// or the position of the entry itself if not found.
+func (l *locator) reference(line int, value string) (int, int) {
+	if line == 0 {
+		return 0, 0
+	}
+	pattern := regexp.MustCompile(`(^|[\s\[,"'-])(` + regexp.QuoteMeta(value) + `)($|[\s\],"'#])`)
+	entryPattern := regexp.MustCompile(`^\s*-\s+name\s*:`)
+	for index := line; index < len(l.lines); index++ {
+		if entryPattern.MatchString(l.lines[index]) {
+			break
+		}
+		if match := pattern.FindStringSubmatchIndex(l.lines[index]); match != nil {
+			return index + 1, match[4] + 1
+		}
+	}
+	return line, 0
+}
@@ -0,0 +1,195 @@
+package promotions
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	client "ontrack-cli/client"
+)
+
+// Kinds of edges in a graph
+const (
+	// A validation stamp needed by a promotion level
+	EdgeValidation = "validation"
+	// A validation stamp included by the include & exclude expressions of a promotion level
+	EdgeInclude = "include"
+	// A promotion level needed by another one
+	EdgePromotion = "promotion"
+)
+
+// Graph is the quality model of a branch: the validation stamps and promotion levels
+// are the nodes, and the auto promotion criteria are the edges
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Node is a validation stamp or a promotion level
+type Node struct {
+	// Unique identifier, like "validation:unit-tests" or "promotion:GOLD"
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Expressions for the validation stamps of a promotion level
+	Include string `json:"include,omitempty"`
+	Exclude string `json:"exclude,omitempty"`
+}
+
+// Edge goes from a validation stamp or a promotion level to the promotion level depending on it
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// NewGraph returns the graph of the validation stamps and promotion levels of a branch,
+// in their order
+func NewGraph(validations []string, levels []client.PromotionLevel) (*Graph, error) {
+	graph := &Graph{Nodes: []Node{}, Edges: []Edge{}}
+	// Validation stamps needed by the promotion levels but missing on the branch
+	validations = slices.Clone(validations)
+	for _, level := range levels {
+		if level.AutoPromotion != nil {
+			for _, validation := range level.AutoPromotion.ValidationStamps {
+				if !slices.Contains(validations, validation) {
+					validations = append(validations, validation)
+				}
+			}
+		}
+	}
+	for _, validation := range validations {
+		graph.Nodes = append(graph.Nodes, Node{ID: nodeID(KindValidation, validation), Kind: KindValidation, Name: validation})
+	}
+	for _, level := range levels {
+		node := Node{ID: nodeID(KindPromotion, level.Name), Kind: KindPromotion, Name: level.Name}
+		if auto := level.AutoPromotion; auto != nil {
+			node.Include = auto.Include
+			node.Exclude = auto.Exclude
+			for _, validation := range auto.ValidationStamps {
+				graph.Edges = append(graph.Edges, Edge{From: nodeID(KindValidation, validation), To: node.ID, Kind: EdgeValidation})
+			}
+			if auto.Include != "" {
+				include, err := regexp.Compile("^(?:" + auto.Include + ")$")
+				if err != nil {
+					return nil, fmt.Errorf("promotion %s: invalid include expression %q", level.Name, auto.Include)
+				}
+				var exclude *regexp.Regexp
+				if auto.Exclude != "" {
+					exclude, err = regexp.Compile("^(?:" + auto.Exclude + ")$")
+					if err != nil {
+						return nil, fmt.Errorf("promotion %s: invalid exclude expression %q", level.Name, auto.Exclude)
+					}
+				}
+				for _, validation := range validations {
+					if slices.Contains(auto.ValidationStamps, validation) || !include.MatchString(validation) || (exclude != nil && exclude.MatchString(validation)) {
+						continue
+					}
+					graph.Edges = append(graph.Edges, Edge{From: nodeID(KindValidation, validation), To: node.ID, Kind: EdgeInclude})
+				}
+			}
+			for _, promotion := range auto.PromotionLevels {
+				graph.Edges = append(graph.Edges, Edge{From: nodeID(KindPromotion, promotion), To: node.ID, Kind: EdgePromotion})
+			}
+		}
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	return graph, nil
+}
+
+// Graph returns the graph of the validations and promotions of the file
+func (c *Config) Graph() (*Graph, error) {
+	var validations []string
+	for _, validation := range c.Validations {
+		validations = append(validations, validation.Name)
+	}
+	var levels []client.PromotionLevel
+	for _, promotion := range c.Promotions {
+		levels = append(levels, client.PromotionLevel{
+			Name:        promotion.Name,
+			Description: promotion.Description,
+			AutoPromotion: &client.AutoPromotion{
+				ValidationStamps: promotion.Validations,
+				PromotionLevels:  promotion.Promotions,
+				Include:          promotion.Include,
+				Exclude:          promotion.Exclude,
+			},
+		})
+	}
+	return NewGraph(validations, levels)
+}
+
+func nodeID(kind string, name string) string {
+	return kind + ":" + name
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format
+func (g *Graph) WriteDOT(w io.Writer, title string) {
+	fmt.Fprintf(w, "digraph %s {\n", strconv.Quote(title))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	for _, node := range g.Nodes {
+		if node.Kind == KindValidation {
+			fmt.Fprintf(w, "  %s [label=%s, shape=box];\n", strconv.Quote(node.ID), strconv.Quote(node.Name))
+		} else {
+			fmt.Fprintf(w, "  %s [label=%s, shape=ellipse, style=bold];\n", strconv.Quote(node.ID), strconv.Quote(node.label()))
+		}
+	}
+	for _, edge := range g.Edges {
+		style := ""
+		switch edge.Kind {
+		case EdgeInclude:
+			style = " [style=dashed]"
+		case EdgePromotion:
+			style = " [style=bold]"
+		}
+		fmt.Fprintf(w, "  %s -> %s%s;\n", strconv.Quote(edge.From), strconv.Quote(edge.To), style)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart
+func (g *Graph) WriteMermaid(w io.Writer) {
+	// Mermaid identifiers cannot contain any special character
+	ids := make(map[string]string)
+	for index, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", index)
+	}
+	fmt.Fprintln(w, "flowchart LR")
+	for _, node := range g.Nodes {
+		if node.Kind == KindValidation {
+			fmt.Fprintf(w, "  %s[\"%s\"]\n", ids[node.ID], mermaidText(node.Name))
+		} else {
+			fmt.Fprintf(w, "  %s([\"%s\"])\n", ids[node.ID], mermaidText(node.label()))
+		}
+	}
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Kind {
+		case EdgeInclude:
+			arrow = "-.->"
+		case EdgePromotion:
+			arrow = "==>"
+		}
+		fmt.Fprintf(w, "  %s %s %s\n", ids[edge.From], arrow, ids[edge.To])
+	}
+}
+
+// label of a promotion node, with its include & exclude expressions
+func (n *Node) label() string {
+	label := n.Name
+	if n.Include != "" {
+		label += "\ninclude: " + n.Include
+	}
+	if n.Exclude != "" {
+		label += "\nexclude: " + n.Exclude
+	}
+	return label
+}
+
+func mermaidText(text string) string {
+	text = strings.ReplaceAll(text, `"`, "#quot;")
+	return strings.ReplaceAll(text, "\n", "<br/>")
+}
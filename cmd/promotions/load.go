@@ -0,0 +1,175 @@
+package promotions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// PropertyPrefix is the prefix of the extended files stored in a property of the project,
+// like "property:promotions"
+const PropertyPrefix = "property:"
+
+// Resolver returns the content of an extended file stored in a property of the project
+type Resolver func(reference string) ([]byte, error)
+
+// Load reads a promotions file together with the files it extends, and applies the overrides
+// of the branches matching the given branch names. No override is applied if no branch name is given.
+//
+// The extended files are merged in order, the file itself coming last. A validation or a promotion
+// replaces the one with the same name defined before, at the same position. The matching branch
+// overrides are then applied in order, the same way.
+func Load(path string, branch []string, resolve Resolver) (*Config, error) {
+	l := &loader{resolve: resolve}
+	config, err := l.load(path, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Branch overrides
+	var problems []Problem
+	overridden := false
+	for _, override := range config.Branches {
+		pattern, err := regexp.Compile("^(?:" + override.Branch + ")$")
+		if err != nil {
+			problems = append(problems, Problem{Message: fmt.Sprintf("invalid branch expression %q", override.Branch)})
+			continue
+		}
+		if slices.ContainsFunc(branch, pattern.MatchString) {
+			overridden = true
+			config.merge(&Config{Validations: override.Validations, Promotions: override.Promotions})
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &Error{File: path, Problems: problems}
+	}
+
+	// Checks, with the positions in the file only if it has not been merged
+	locate := newLocator("")
+	if !overridden && len(l.files) == 1 {
+		locate = newLocator(l.contents[0])
+	}
+	if problems := config.check(locate); len(problems) > 0 {
+		return nil, &Error{File: path, Problems: problems}
+	}
+	return config, nil
+}
+
+type loader struct {
+	resolve Resolver
+	// Files being loaded, to detect cycles
+	stack []string
+	// Loaded files & their contents
+	files    []string
+	contents []string
+}
+
+// load reads a file and the files it extends, relative to the file extending it
+func (l *loader) load(reference string, from string) (*Config, error) {
+	var name string
+	var content []byte
+	var err error
+	if strings.HasPrefix(reference, PropertyPrefix) {
+		name = reference
+		if l.resolve == nil {
+			return nil, fmt.Errorf("%s: a project is needed to extend %s", from, reference)
+		}
+		content, err = l.resolve(strings.TrimPrefix(reference, PropertyPrefix))
+	} else {
+		name = reference
+		if from != "" && !strings.HasPrefix(from, PropertyPrefix) && !filepath.IsAbs(reference) {
+			name = filepath.Join(filepath.Dir(from), reference)
+		}
+		content, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(l.stack, name) {
+		return nil, fmt.Errorf("%s: cycle between the extended files: %s -> %s", from, strings.Join(l.stack, " -> "), name)
+	}
+	l.files = append(l.files, name)
+	l.contents = append(l.contents, string(content))
+
+	own, err := decode(name, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(own.Extends) == 0 && from == "" {
+		// Single file, checked as a whole
+		return own, nil
+	}
+	// The duplicates would be hidden by the merge
+	if problems := own.duplicates(newLocator(string(content))); len(problems) > 0 {
+		return nil, &Error{File: name, Problems: problems}
+	}
+
+	// Base files first
+	l.stack = append(l.stack, name)
+	config := &Config{}
+	for _, base := range own.Extends {
+		extended, err := l.load(base, name)
+		if err != nil {
+			return nil, err
+		}
+		config.merge(extended)
+	}
+	l.stack = l.stack[:len(l.stack)-1]
+
+	config.merge(own)
+	return config, nil
+}
+
+// merge adds the validations, promotions and branch overrides of another file
+func (c *Config) merge(other *Config) {
+	c.Validations = mergeByName(c.Validations, other.Validations, func(v Validation) string { return v.Name })
+	c.Promotions = mergeByName(c.Promotions, other.Promotions, func(p Promotion) string { return p.Name })
+	c.Branches = append(c.Branches, other.Branches...)
+}
+
+// mergeByName replaces the items having the same name, and appends the other ones
+func mergeByName[T any](items []T, others []T, name func(T) string) []T {
+	for _, other := range others {
+		index := slices.IndexFunc(items, func(item T) bool { return name(item) == name(other) })
+		if index >= 0 {
+			items[index] = other
+		} else {
+			items = append(items, other)
+		}
+	}
+	return items
+}
+
+// Environment variables, like ${NAME} or ${NAME:-default}, $$ being an escaped $
+var variablePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// interpolate replaces the environment variables in the content of a file.
+// The variables which are not defined and have no default value are reported.
+func interpolate(content string) (string, []Problem) {
+	var problems []Problem
+	lines := strings.Split(content, "\n")
+	for index, line := range lines {
+		lines[index] = variablePattern.ReplaceAllStringFunc(line, func(match string) string {
+			if match == "$$" {
+				return "$"
+			}
+			groups := variablePattern.FindStringSubmatch(match)
+			if value, ok := os.LookupEnv(groups[1]); ok {
+				return value
+			}
+			if strings.Contains(match, ":-") {
+				return groups[2]
+			}
+			problems = append(problems, Problem{
+				Line:    index + 1,
+				Column:  strings.Index(line, match) + 1,
+				Message: fmt.Sprintf("environment variable %s is not defined", groups[1]),
+			})
+			return match
+		})
+	}
+	return strings.Join(lines, "\n"), problems
+}
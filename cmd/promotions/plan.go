@@ -0,0 +1,335 @@
+package promotions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
+)
+
+// Actions of a planned change
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionNone   = "no-op"
+)
+
+// Kinds of entities in a plan
+const (
+	KindValidation = "validation"
+	KindPromotion  = "promotion"
+)
+
+// Change is a planned change for a validation stamp or a promotion level of a branch
+type Change struct {
+	Kind   string
+	Name   string
+	Action string
+	// ID of the existing validation stamp or promotion level, 0 when created
+	ID int
+	// What is updated
+	Details []string
+}
+
+// Plan compares the file with the validation stamps and promotion levels of a branch.
+// The validation stamps and promotion levels not declared in the file are planned for
+// deletion only if prune is true.
+func Plan(config *Config, stamps []client.ValidationStamp, levels []client.PromotionLevel, prune bool) ([]Change, error) {
+	var changes []Change
+
+	// Validations declared in the file
+	var declared []string
+	for _, validation := range config.Validations {
+		declared = append(declared, validation.Name)
+		change := Change{Kind: KindValidation, Name: validation.Name, Action: ActionCreate}
+		if stamp := findStamp(stamps, validation.Name); stamp != nil {
+			details, err := validation.diff(stamp)
+			if err != nil {
+				return nil, err
+			}
+			change.ID = stamp.ID
+			change.Details = details
+			change.Action = ActionNone
+			if len(details) > 0 {
+				change.Action = ActionUpdate
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	// Validations only used by the promotions
+	for _, promotion := range config.Promotions {
+		for _, name := range promotion.Validations {
+			if slices.Contains(declared, name) {
+				continue
+			}
+			declared = append(declared, name)
+			change := Change{Kind: KindValidation, Name: name, Action: ActionCreate}
+			if stamp := findStamp(stamps, name); stamp != nil {
+				change.ID = stamp.ID
+				change.Action = ActionNone
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	// Promotions
+	var promotions []string
+	for _, promotion := range config.Promotions {
+		promotions = append(promotions, promotion.Name)
+		change := Change{Kind: KindPromotion, Name: promotion.Name, Action: ActionCreate}
+		if level := findLevel(levels, promotion.Name); level != nil {
+			change.ID = level.ID
+			change.Details = promotion.diff(level)
+			change.Action = ActionNone
+			if len(change.Details) > 0 {
+				change.Action = ActionUpdate
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	// Pruning
+	if prune {
+		for _, level := range levels {
+			if !slices.Contains(promotions, level.Name) {
+				changes = append(changes, Change{Kind: KindPromotion, Name: level.Name, Action: ActionDelete, ID: level.ID})
+			}
+		}
+		for _, stamp := range stamps {
+			if !slices.Contains(declared, stamp.Name) {
+				changes = append(changes, Change{Kind: KindValidation, Name: stamp.Name, Action: ActionDelete, ID: stamp.ID})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// Planned is a change of a plan, having an action
+type Planned interface {
+	PlannedAction() string
+}
+
+// PlannedAction returns the action of the change
+func (c Change) PlannedAction() string {
+	return c.Action
+}
+
+// Count returns the number of changes having the given action
+func Count[C Planned](changes []C, action string) int {
+	count := 0
+	for _, change := range changes {
+		if change.PlannedAction() == action {
+			count++
+		}
+	}
+	return count
+}
+
+// ActionSymbols are the symbols of the actions in a printed plan
+var ActionSymbols = map[string]string{
+	ActionCreate: "+",
+	ActionUpdate: "~",
+	ActionDelete: "-",
+	ActionNone:   " ",
+}
+
+// PrintPlan prints the changes, grouped by kind, followed by a summary
+func PrintPlan(w io.Writer, changes []Change) {
+	for _, kind := range []string{KindValidation, KindPromotion} {
+		title := "Validation stamps:"
+		if kind == KindPromotion {
+			title = "Promotion levels:"
+		}
+		fmt.Fprintln(w, title)
+		for _, change := range changes {
+			if change.Kind != kind {
+				continue
+			}
+			fmt.Fprintf(w, "  %s %s\n", ActionSymbols[change.Action], change.Name)
+			for _, detail := range change.Details {
+				fmt.Fprintf(w, "      %s\n", detail)
+			}
+		}
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete, %d unchanged.\n",
+		Count(changes, ActionCreate),
+		Count(changes, ActionUpdate),
+		Count(changes, ActionDelete),
+		Count(changes, ActionNone),
+	)
+}
+
+func findStamp(stamps []client.ValidationStamp, name string) *client.ValidationStamp {
+	for index := range stamps {
+		if stamps[index].Name == name {
+			return &stamps[index]
+		}
+	}
+	return nil
+}
+
+func findLevel(levels []client.PromotionLevel, name string) *client.PromotionLevel {
+	for index := range levels {
+		if levels[index].Name == name {
+			return &levels[index]
+		}
+	}
+	return nil
+}
+
+// desiredDataType returns the FQCN and the configuration of the data type of a validation,
+// an empty FQCN meaning that the validation has no data type.
+func (v *Validation) desiredDataType() (string, interface{}, error) {
+	switch {
+	case v.Tests != nil:
+		return datatypes.Resolve("tests"), map[string]interface{}{
+			"warningIfSkipped": v.Tests.WarningIfSkipped,
+		}, nil
+	case v.CHML != nil:
+		return datatypes.Resolve("chml"), map[string]interface{}{
+			"warningLevel": v.CHML.WarningLevel,
+			"failedLevel":  v.CHML.FailedLevel,
+		}, nil
+	case v.Percentage != nil:
+		return datatypes.Resolve("percentage"), v.Percentage, nil
+	case v.Metrics:
+		return datatypes.Resolve("metrics"), nil, nil
+	case v.DataType != nil:
+		dataType := datatypes.Resolve(*v.DataType)
+		if v.DataTypeConfig == nil {
+			return dataType, nil, nil
+		}
+		value, err := datatypes.ParseValue(*v.DataTypeConfig)
+		return dataType, value, err
+	default:
+		return "", nil, nil
+	}
+}
+
+// diff lists the differences between a validation and an existing validation stamp
+func (v *Validation) diff(stamp *client.ValidationStamp) ([]string, error) {
+	var details []string
+	if v.Description != stamp.Description {
+		details = append(details, fmt.Sprintf("description: %q -> %q", stamp.Description, v.Description))
+	}
+
+	dataType, dataConfig, err := v.desiredDataType()
+	if err != nil {
+		return nil, fmt.Errorf("validation %s: %w", v.Name, err)
+	}
+	var currentType string
+	var currentConfig interface{}
+	if stamp.DataType != nil {
+		currentType = stamp.DataType.Descriptor.ID
+		currentConfig = stamp.DataType.Config
+	}
+	if dataType != currentType {
+		details = append(details, fmt.Sprintf("data type: %s -> %s", dataTypeAlias(currentType), dataTypeAlias(dataType)))
+	} else if dataType != "" {
+		current, desired := Normalize(currentConfig), Normalize(dataConfig)
+		if !reflect.DeepEqual(current, desired) {
+			details = append(details, fmt.Sprintf("data type config: %s -> %s", Literal(current), Literal(desired)))
+		}
+	}
+	return details, nil
+}
+
+// diff lists the differences between a promotion and an existing promotion level
+func (p *Promotion) diff(level *client.PromotionLevel) []string {
+	var details []string
+	if p.Description != level.Description {
+		details = append(details, fmt.Sprintf("description: %q -> %q", level.Description, p.Description))
+	}
+
+	// The auto promotion is set only when some criteria are defined
+	if len(p.Validations) == 0 && len(p.Promotions) == 0 && p.Include == "" {
+		return details
+	}
+	current := level.AutoPromotion
+	if current == nil {
+		current = &client.AutoPromotion{}
+	}
+	if !sameItems(current.ValidationStamps, p.Validations) {
+		details = append(details, fmt.Sprintf("validations: [%s] -> [%s]", strings.Join(current.ValidationStamps, ", "), strings.Join(p.Validations, ", ")))
+	}
+	if !sameItems(current.PromotionLevels, p.Promotions) {
+		details = append(details, fmt.Sprintf("promotions: [%s] -> [%s]", strings.Join(current.PromotionLevels, ", "), strings.Join(p.Promotions, ", ")))
+	}
+	if current.Include != p.Include {
+		details = append(details, fmt.Sprintf("include: %q -> %q", current.Include, p.Include))
+	}
+	if current.Exclude != p.Exclude {
+		details = append(details, fmt.Sprintf("exclude: %q -> %q", current.Exclude, p.Exclude))
+	}
+	return details
+}
+
+func sameItems(current []string, desired []string) bool {
+	a, b := slices.Clone(current), slices.Clone(desired)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+func dataTypeAlias(dataType string) string {
+	if dataType == "" {
+		return "none"
+	}
+	if known := datatypes.Find(dataType); known != nil {
+		return known.Alias
+	}
+	return dataType
+}
+
+// Normalize converts a configuration to its JSON representation, without the null fields,
+// so that it can be compared with the configuration returned by Ontrack
+func Normalize(value interface{}) interface{} {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(content, &normalized); err != nil {
+		return value
+	}
+	return WithoutNulls(normalized)
+}
+
+// WithoutNulls removes the null fields of a JSON value, which are the default values.
+// An object having no field left is nil.
+func WithoutNulls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if item == nil {
+				delete(v, key)
+			} else {
+				v[key] = WithoutNulls(item)
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+	case []interface{}:
+		for index, item := range v {
+			v[index] = WithoutNulls(item)
+		}
+	}
+	return value
+}
+
+// Literal prints a JSON value in a plan, "none" when nil
+func Literal(value interface{}) string {
+	if value == nil {
+		return "none"
+	}
+	return datatypes.GraphQLLiteral(value)
+}
@@ -0,0 +1,192 @@
+package promotions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the definition of the validations and promotions of a branch,
+// as found in the .ontrack/promotions.yaml file
+type Config struct {
+	// Files this file is based on, as local paths or as references to project properties
+	Extends []string `yaml:"extends,omitempty" json:"extends,omitempty"`
+	// List of validations and their configuration
+	Validations []Validation `yaml:"validations,omitempty" json:"validations,omitempty"`
+	// List of promotions
+	Promotions []Promotion `yaml:"promotions,omitempty" json:"promotions,omitempty"`
+	// Overrides for some branches
+	Branches []BranchConfig `yaml:"branches,omitempty" json:"branches,omitempty"`
+}
+
+// BranchConfig overrides the validations and promotions for the branches matching a regular expression
+type BranchConfig struct {
+	// Regular expression for the names of the branches
+	Branch string `yaml:"branch" json:"branch"`
+	// Validations replacing or completing the ones of the file
+	Validations []Validation `yaml:"validations,omitempty" json:"validations,omitempty"`
+	// Promotions replacing or completing the ones of the file
+	Promotions []Promotion `yaml:"promotions,omitempty" json:"promotions,omitempty"`
+}
+
+type Validation struct {
+	// Name of the validation
+	Name string `yaml:"name" json:"name"`
+	// Optional description for the validation
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// Optional data type
+	DataType *string `yaml:"dataType,omitempty" json:"dataType,omitempty"`
+	// Optional data type config
+	DataTypeConfig *string `yaml:"dataTypeConfig,omitempty" json:"dataTypeConfig,omitempty"`
+	// Test configuration
+	Tests *TestsConfig `yaml:"tests,omitempty" json:"tests,omitempty"`
+	// CHML configuration
+	CHML *CHMLConfig `yaml:"chml,omitempty" json:"chml,omitempty"`
+	// Percentage configuration
+	Percentage *PercentageConfig `yaml:"percentage,omitempty" json:"percentage,omitempty"`
+	// Metrics
+	Metrics bool `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+}
+
+type TestsConfig struct {
+	// Warning if skipped tests
+	WarningIfSkipped bool `yaml:"warningIfSkipped" json:"warningIfSkipped"`
+}
+
+type CHMLConfig struct {
+	// Threshold for a warning
+	WarningLevel CHMLLevel `yaml:"warningLevel" json:"warningLevel"`
+	// Threshold for a failure
+	FailedLevel CHMLLevel `yaml:"failedLevel" json:"failedLevel"`
+}
+
+type CHMLLevel struct {
+	// CRITICAL, HIGH, MEDIUM or LOW
+	Level string `yaml:"level" json:"level"`
+	// Number of issues of this level
+	Value int `yaml:"value" json:"value"`
+}
+
+type PercentageConfig struct {
+	// Optional threshold for a warning
+	WarningThreshold *int `yaml:"warningThreshold,omitempty" json:"warningThreshold,omitempty"`
+	// Optional threshold for a failure
+	FailureThreshold *int `yaml:"failureThreshold,omitempty" json:"failureThreshold,omitempty"`
+	// Direction of the value scale
+	OkIfGreater bool `yaml:"okIfGreater" json:"okIfGreater"`
+}
+
+type Promotion struct {
+	// Name of the promotion
+	Name string `yaml:"name" json:"name"`
+	// Optional description for the promotion
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	// List of validations
+	Validations []string `yaml:"validations,omitempty" json:"validations,omitempty"`
+	// List of promotions
+	Promotions []string `yaml:"promotions,omitempty" json:"promotions,omitempty"`
+	// Regular expression for the validations to include
+	Include string `yaml:"include,omitempty" json:"include,omitempty"`
+	// Regular expression for the validations to exclude
+	Exclude string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// Problem is an error found in a file, at a given position when known
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// Error lists all the problems found in a file
+type Error struct {
+	File     string
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	var lines []string
+	for _, problem := range e.Problems {
+		switch {
+		case problem.Line > 0 && problem.Column > 0:
+			lines = append(lines, fmt.Sprintf("%s:%d:%d: %s", e.File, problem.Line, problem.Column, problem.Message))
+		case problem.Line > 0:
+			lines = append(lines, fmt.Sprintf("%s:%d: %s", e.File, problem.Line, problem.Message))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s", e.File, problem.Message))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Read reads and checks a promotions file, without any branch override.
+// Only the local files can be extended.
+func Read(path string) (*Config, error) {
+	return Load(path, nil, nil)
+}
+
+// Parse decodes and checks the content of a single promotions file. Unknown fields are rejected.
+// The returned error is an *Error, listing the problems with their position in the file.
+func Parse(path string, content []byte) (*Config, error) {
+	config, err := decode(path, content)
+	if err != nil {
+		return nil, err
+	}
+	if problems := config.check(newLocator(string(content))); len(problems) > 0 {
+		return nil, &Error{File: path, Problems: problems}
+	}
+	return config, nil
+}
+
+// decode interpolates the environment variables in the content of a file and decodes it
+func decode(path string, content []byte) (*Config, error) {
+	text, problems := interpolate(string(content))
+	if len(problems) > 0 {
+		return nil, &Error{File: path, Problems: problems}
+	}
+	var config Config
+	if err := yaml.UnmarshalStrict([]byte(text), &config); err != nil {
+		return nil, &Error{File: path, Problems: yamlProblems(text, err)}
+	}
+	return &config, nil
+}
+
+var (
+	yamlLinePattern         = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	yamlUnknownFieldPattern = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+)
+
+// yamlProblems converts the errors of the YAML decoder, like "line 3: field validation not
+// found in type promotions.Config", into problems with a line and a column.
+func yamlProblems(content string, err error) []Problem {
+	lines := strings.Split(content, "\n")
+	var problems []Problem
+	for _, text := range strings.Split(err.Error(), "\n") {
+		text = strings.TrimSpace(text)
+		if text == "" || text == "yaml: unmarshal errors:" {
+			continue
+		}
+		match := yamlLinePattern.FindStringSubmatch(text)
+		if match == nil {
+			problems = append(problems, Problem{Message: strings.TrimPrefix(text, "yaml: ")})
+			continue
+		}
+		line, _ := strconv.Atoi(match[1])
+		problem := Problem{Line: line, Message: match[2]}
+		if line > 0 && line <= len(lines) {
+			source := lines[line-1]
+			problem.Column = len(source) - len(strings.TrimLeft(source, " -")) + 1
+			if field := yamlUnknownFieldPattern.FindStringSubmatch(match[2]); field != nil {
+				problem.Message = fmt.Sprintf("unknown field %q", field[1])
+				if index := strings.Index(source, field[1]); index >= 0 {
+					problem.Column = index + 1
+				}
+			}
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
@@ -0,0 +1,269 @@
+package promotions
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	client "ontrack-cli/client"
+)
+
+func TestPromotionsValid(t *testing.T) {
+	config, err := Read("promotions_files/valid.yaml")
+	if err != nil {
+		t.Fatalf("Error reading the file: %v", err)
+	}
+	if len(config.Validations) != 4 || len(config.Promotions) != 2 {
+		t.Fatalf("Expected 4 validations and 2 promotions, got %d and %d", len(config.Validations), len(config.Promotions))
+	}
+	if config.Validations[1].CHML == nil || config.Validations[1].CHML.FailedLevel.Level != "CRITICAL" {
+		t.Errorf("CHML configuration not read: %+v", config.Validations[1].CHML)
+	}
+	if config.Promotions[1].Include != "integration-.*" || config.Promotions[1].Promotions[0] != "BRONZE" {
+		t.Errorf("Promotion not read: %+v", config.Promotions[1])
+	}
+}
+
+func TestPromotionsProblems(t *testing.T) {
+
+	tests := []struct {
+		file     string
+		expected []Problem
+	}{
+		{"promotions_files/unknown-field.yaml", []Problem{
+			{5, 5, `unknown field "validation"`},
+		}},
+		{"promotions_files/references.yaml", []Problem{
+			{2, 11, "validation unit-tests: only one type can be set, found tests, metrics"},
+			{6, 11, "validation unit-tests is defined several times"},
+			{14, 9, "promotion SILVER depends on the undefined promotion GOLDD"},
+			{15, 11, "promotion BRONZE is defined several times"},
+		}},
+		{"promotions_files/cycles.yaml", []Problem{
+			{2, 11, "dependency cycle between promotions: BRONZE -> GOLD -> SILVER -> BRONZE"},
+			{8, 11, "dependency cycle between promotions: PLATINUM -> PLATINUM"},
+		}},
+	}
+
+	for _, test := range tests {
+		_, err := Read(test.file)
+		var problems *Error
+		if !errors.As(err, &problems) {
+			t.Errorf("%s - Expected problems, got %v", test.file, err)
+			continue
+		}
+		if len(problems.Problems) != len(test.expected) {
+			t.Errorf("%s - Expected %d problems, got:\n%v", test.file, len(test.expected), err)
+			continue
+		}
+		for index, expected := range test.expected {
+			if actual := problems.Problems[index]; actual != expected {
+				t.Errorf("%s - Expected %+v, Actual %+v", test.file, expected, actual)
+			}
+		}
+	}
+}
+
+func TestPromotionsSyntaxError(t *testing.T) {
+	_, err := Parse("promotions.yaml", []byte("promotions:\n  - name: BRONZE\n   validations: []\n"))
+	if err == nil || err.Error() != "promotions.yaml:2:5: did not find expected '-' indicator" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPromotionsJSONSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(JSONSchema), &schema); err != nil {
+		t.Fatalf("Invalid JSON schema: %v", err)
+	}
+	if schema["type"] != "object" {
+		t.Errorf("Unexpected schema type: %v", schema["type"])
+	}
+}
+
+func TestPromotionsPlan(t *testing.T) {
+	config, err := Read("promotions_files/valid.yaml")
+	if err != nil {
+		t.Fatalf("Error reading the file: %v", err)
+	}
+
+	var stamps []client.ValidationStamp
+	if err := json.Unmarshal([]byte(`[
+		{"id": 1, "name": "unit-tests", "description": "Unit tests", "dataType": {
+			"descriptor": {"id": "net.nemerosa.ontrack.extension.general.validation.TestSummaryValidationDataType"},
+			"config": {"warningIfSkipped": true}
+		}},
+		{"id": 2, "name": "coverage", "dataType": {
+			"descriptor": {"id": "net.nemerosa.ontrack.extension.general.validation.ThresholdPercentageValidationDataType"},
+			"config": {"warningThreshold": 80, "failureThreshold": null, "okIfGreater": true}
+		}},
+		{"id": 3, "name": "lint", "description": "Lint"},
+		{"id": 4, "name": "old"}
+	]`), &stamps); err != nil {
+		t.Fatal(err)
+	}
+	levels := []client.PromotionLevel{
+		{ID: 1, Name: "BRONZE", Description: "Built and tested", AutoPromotion: &client.AutoPromotion{
+			ValidationStamps: []string{"lint", "unit-tests"},
+		}},
+		{ID: 2, Name: "SILVER", AutoPromotion: &client.AutoPromotion{
+			PromotionLevels: []string{"BRONZE"},
+			Include:         "integration-.*",
+		}},
+		{ID: 3, Name: "GOLD"},
+	}
+
+	changes, err := Plan(config, stamps, levels, true)
+	if err != nil {
+		t.Fatalf("Error planning: %v", err)
+	}
+	expected := []struct {
+		name    string
+		action  string
+		details int
+	}{
+		{"unit-tests", ActionNone, 0},
+		{"security", ActionCreate, 0},
+		{"coverage", ActionNone, 0},
+		{"ratio", ActionCreate, 0},
+		{"lint", ActionNone, 0},
+		{"BRONZE", ActionNone, 0},
+		{"SILVER", ActionUpdate, 1},
+		{"GOLD", ActionDelete, 0},
+		{"old", ActionDelete, 0},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %+v", len(expected), changes)
+	}
+	for index, change := range changes {
+		if change.Name != expected[index].name || change.Action != expected[index].action || len(change.Details) != expected[index].details {
+			t.Errorf("Expected %+v, Actual %+v", expected[index], change)
+		}
+	}
+}
+
+func TestPromotionsExtends(t *testing.T) {
+	resolver := func(name string) ([]byte, error) {
+		if name != "promotions" {
+			t.Fatalf("Unexpected property: %s", name)
+		}
+		return []byte("validations:\n  - name: security\n    metrics: true\n"), nil
+	}
+
+	tests := []struct {
+		branch      []string
+		validations []string
+		gold        []string
+	}{
+		{nil, []string{"unit-tests", "lint", "security"}, nil},
+		{[]string{"release/1.0", "release-1.0"}, []string{"unit-tests", "lint", "security"}, []string{"security"}},
+		{[]string{"main"}, []string{"unit-tests", "lint", "security"}, nil},
+	}
+
+	for _, test := range tests {
+		config, err := Load("promotions_files/extends/promotions.yaml", test.branch, resolver)
+		if err != nil {
+			t.Fatalf("%v - Error loading the file: %v", test.branch, err)
+		}
+		var validations []string
+		for _, validation := range config.Validations {
+			validations = append(validations, validation.Name)
+		}
+		if !slices.Equal(validations, test.validations) {
+			t.Errorf("%v - Expected validations %v, Actual %v", test.branch, test.validations, validations)
+		}
+		if unitTests := config.Validations[0]; unitTests.Description != "Unit tests" || !unitTests.Tests.WarningIfSkipped {
+			t.Errorf("%v - Validation not replaced: %+v", test.branch, unitTests)
+		}
+		var promotions []string
+		for _, promotion := range config.Promotions {
+			promotions = append(promotions, promotion.Name)
+		}
+		if !slices.Equal(promotions, []string{"BRONZE", "SILVER", "GOLD"}) {
+			t.Errorf("%v - Unexpected promotions %v", test.branch, promotions)
+		}
+		if gold := config.Promotions[2]; !slices.Equal(gold.Validations, test.gold) {
+			t.Errorf("%v - Expected GOLD validations %v, Actual %v", test.branch, test.gold, gold.Validations)
+		}
+	}
+
+	// Without any project
+	if _, err := Read("promotions_files/extends/promotions.yaml"); err == nil || !strings.Contains(err.Error(), "a project is needed to extend property:promotions") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Cycles
+	if _, err := Read("promotions_files/extends/cycle.yaml"); err == nil || !strings.Contains(err.Error(), "cycle between the extended files") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPromotionsEnvironmentVariables(t *testing.T) {
+	t.Setenv("PROMOTION", "GOLD")
+	config, err := Parse("promotions.yaml", []byte("promotions:\n  - name: ${PROMOTION}\n    description: ${DESCRIPTION:-Gold} $$HOME\n"))
+	if err != nil {
+		t.Fatalf("Error parsing the file: %v", err)
+	}
+	if promotion := config.Promotions[0]; promotion.Name != "GOLD" || promotion.Description != "Gold $HOME" {
+		t.Errorf("Variables not replaced: %+v", promotion)
+	}
+
+	_, err = Parse("promotions.yaml", []byte("promotions:\n  - name: ${UNDEFINED_PROMOTION}\n"))
+	if err == nil || err.Error() != "promotions.yaml:2:11: environment variable UNDEFINED_PROMOTION is not defined" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestPromotionsGraph(t *testing.T) {
+	graph, err := NewGraph([]string{"unit-tests", "integration-fast", "integration-slow-1"}, []client.PromotionLevel{
+		{Name: "BRONZE", AutoPromotion: &client.AutoPromotion{ValidationStamps: []string{"unit-tests", "lint"}}},
+		{Name: "SILVER", AutoPromotion: &client.AutoPromotion{
+			PromotionLevels: []string{"BRONZE"},
+			Include:         "integration-.*",
+			Exclude:         "integration-slow-.*",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Error building the graph: %v", err)
+	}
+
+	var dot strings.Builder
+	graph.WriteDOT(&dot, "P/main")
+	expected := `digraph "P/main" {
+  rankdir=LR;
+  "validation:unit-tests" [label="unit-tests", shape=box];
+  "validation:integration-fast" [label="integration-fast", shape=box];
+  "validation:integration-slow-1" [label="integration-slow-1", shape=box];
+  "validation:lint" [label="lint", shape=box];
+  "promotion:BRONZE" [label="BRONZE", shape=ellipse, style=bold];
+  "promotion:SILVER" [label="SILVER\ninclude: integration-.*\nexclude: integration-slow-.*", shape=ellipse, style=bold];
+  "validation:unit-tests" -> "promotion:BRONZE";
+  "validation:lint" -> "promotion:BRONZE";
+  "validation:integration-fast" -> "promotion:SILVER" [style=dashed];
+  "promotion:BRONZE" -> "promotion:SILVER" [style=bold];
+}
+`
+	if dot.String() != expected {
+		t.Errorf("Expected:\n%s\nActual:\n%s", expected, dot.String())
+	}
+
+	var mermaid strings.Builder
+	graph.WriteMermaid(&mermaid)
+	expected = `flowchart LR
+  n0["unit-tests"]
+  n1["integration-fast"]
+  n2["integration-slow-1"]
+  n3["lint"]
+  n4(["BRONZE"])
+  n5(["SILVER<br/>include: integration-.*<br/>exclude: integration-slow-.*"])
+  n0 --> n4
+  n3 --> n4
+  n1 -.-> n5
+  n4 ==> n5
+`
+	if mermaid.String() != expected {
+		t.Errorf("Expected:\n%s\nActual:\n%s", expected, mermaid.String())
+	}
+}
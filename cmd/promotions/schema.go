@@ -0,0 +1,10 @@
+package promotions
+
+import (
+	_ "embed"
+)
+
+// JSONSchema is the JSON Schema of the promotions file, to be used by editors
+//
+//go:embed promotions.schema.json
+var JSONSchema string
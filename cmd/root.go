@@ -22,6 +22,7 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -83,7 +84,14 @@ Examples of usages:
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	if err := rootCmd.Execute(); err != nil {
+		// Errors with a specific exit code have already been reported by Cobra
+		var exitError *ExitError
+		if errors.As(err, &exitError) {
+			os.Exit(exitError.Code)
+		}
+		cobra.CheckErr(err)
+	}
 }
 
 func init() {
@@ -0,0 +1,316 @@
+package scan
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// SARIF 2.1
+
+type sarifReport struct {
+	Runs []struct {
+		Tool struct {
+			Driver struct {
+				Rules []sarifRule
+			}
+		}
+		Results []struct {
+			RuleID  string
+			Level   string
+			Message struct {
+				Text string
+			}
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string
+					}
+				}
+			}
+			Properties map[string]interface{}
+		}
+	}
+}
+
+type sarifRule struct {
+	ID                   string
+	DefaultConfiguration struct {
+		Level string
+	}
+	Properties map[string]interface{}
+}
+
+func parseSARIF(buf []byte) ([]Finding, error) {
+	var report sarifReport
+	if err := json.Unmarshal(buf, &report); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, run := range report.Runs {
+		rules := make(map[string]sarifRule)
+		for _, rule := range run.Tool.Driver.Rules {
+			rules[rule.ID] = rule
+		}
+		for _, result := range run.Results {
+			rule := rules[result.RuleID]
+			// The "security-severity" property (CVSS score) takes precedence over the SARIF level
+			severity := ""
+			if score, ok := securitySeverity(result.Properties); ok {
+				severity = cvssSeverity(score)
+			} else if score, ok := securitySeverity(rule.Properties); ok {
+				severity = cvssSeverity(score)
+			} else if result.Level != "" {
+				severity = Severity(result.Level)
+			} else if rule.DefaultConfiguration.Level != "" {
+				severity = Severity(rule.DefaultConfiguration.Level)
+			} else {
+				// SARIF default level
+				severity = Medium
+			}
+			if severity == "" {
+				continue
+			}
+			path := ""
+			if len(result.Locations) > 0 {
+				path = result.Locations[0].PhysicalLocation.ArtifactLocation.URI
+			}
+			findings = append(findings, Finding{
+				ID:       result.RuleID,
+				Severity: severity,
+				Path:     path,
+				Title:    result.Message.Text,
+			})
+		}
+	}
+	return findings, nil
+}
+
+func securitySeverity(properties map[string]interface{}) (float64, bool) {
+	switch value := properties["security-severity"].(type) {
+	case string:
+		score, err := strconv.ParseFloat(value, 64)
+		return score, err == nil
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+// Trivy JSON
+
+type trivyReport struct {
+	Results []struct {
+		Target          string
+		Vulnerabilities []struct {
+			VulnerabilityID string
+			PkgName         string
+			Severity        string
+			Title           string
+		}
+		Misconfigurations []struct {
+			ID       string
+			Severity string
+			Title    string
+		}
+		Secrets []struct {
+			RuleID   string
+			Severity string
+			Title    string
+		}
+	}
+}
+
+func parseTrivy(buf []byte) ([]Finding, error) {
+	var report trivyReport
+	if err := json.Unmarshal(buf, &report); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	add := func(id string, severity string, path string, title string) {
+		if level := Severity(severity); level != "" {
+			findings = append(findings, Finding{ID: id, Severity: level, Path: path, Title: title})
+		}
+	}
+	for _, result := range report.Results {
+		for _, vulnerability := range result.Vulnerabilities {
+			path := result.Target
+			if vulnerability.PkgName != "" {
+				path = result.Target + ":" + vulnerability.PkgName
+			}
+			add(vulnerability.VulnerabilityID, vulnerability.Severity, path, vulnerability.Title)
+		}
+		for _, misconfiguration := range result.Misconfigurations {
+			add(misconfiguration.ID, misconfiguration.Severity, result.Target, misconfiguration.Title)
+		}
+		for _, secret := range result.Secrets {
+			add(secret.RuleID, secret.Severity, result.Target, secret.Title)
+		}
+	}
+	return findings, nil
+}
+
+// Grype JSON
+
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID          string
+			Severity    string
+			Description string
+		}
+		Artifact struct {
+			Name      string
+			Version   string
+			Locations []struct {
+				Path string
+			}
+		}
+	}
+}
+
+func parseGrype(buf []byte) ([]Finding, error) {
+	var report grypeReport
+	if err := json.Unmarshal(buf, &report); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, match := range report.Matches {
+		severity := Severity(match.Vulnerability.Severity)
+		if severity == "" {
+			continue
+		}
+		path := match.Artifact.Name
+		if len(match.Artifact.Locations) > 0 {
+			path = match.Artifact.Locations[0].Path
+		}
+		findings = append(findings, Finding{
+			ID:       match.Vulnerability.ID,
+			Severity: severity,
+			Path:     path,
+			Title:    match.Vulnerability.Description,
+		})
+	}
+	return findings, nil
+}
+
+// OWASP Dependency-Check JSON
+
+type dependencyCheckReport struct {
+	Dependencies []struct {
+		FileName        string
+		FilePath        string
+		Vulnerabilities []struct {
+			Name        string
+			Severity    string
+			Description string
+		}
+	}
+}
+
+func parseDependencyCheck(buf []byte) ([]Finding, error) {
+	var report dependencyCheckReport
+	if err := json.Unmarshal(buf, &report); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, dependency := range report.Dependencies {
+		path := dependency.FilePath
+		if path == "" {
+			path = dependency.FileName
+		}
+		for _, vulnerability := range dependency.Vulnerabilities {
+			severity := Severity(vulnerability.Severity)
+			if severity == "" {
+				continue
+			}
+			findings = append(findings, Finding{
+				ID:       vulnerability.Name,
+				Severity: severity,
+				Path:     path,
+				Title:    vulnerability.Description,
+			})
+		}
+	}
+	return findings, nil
+}
+
+// npm audit --json (both the npm 6 "advisories" and the npm 7+ "vulnerabilities" formats)
+
+type npmAuditReport struct {
+	Advisories map[string]struct {
+		ID         int
+		ModuleName string `json:"module_name"`
+		Severity   string
+		Title      string
+		CVEs       []string
+	}
+	Vulnerabilities map[string]struct {
+		Name     string
+		Severity string
+		Nodes    []string
+		Via      []json.RawMessage
+	}
+}
+
+func parseNpmAudit(buf []byte) ([]Finding, error) {
+	var report npmAuditReport
+	if err := json.Unmarshal(buf, &report); err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	// npm 6
+	for _, advisory := range report.Advisories {
+		severity := Severity(advisory.Severity)
+		if severity == "" {
+			continue
+		}
+		id := strconv.Itoa(advisory.ID)
+		if len(advisory.CVEs) > 0 {
+			id = advisory.CVEs[0]
+		}
+		findings = append(findings, Finding{
+			ID:       id,
+			Severity: severity,
+			Path:     advisory.ModuleName,
+			Title:    advisory.Title,
+		})
+	}
+	// npm 7+
+	for name, vulnerability := range report.Vulnerabilities {
+		severity := Severity(vulnerability.Severity)
+		if severity == "" {
+			continue
+		}
+		location := name
+		if len(vulnerability.Nodes) > 0 {
+			location = vulnerability.Nodes[0]
+		}
+		// Advisory details, when the vulnerability is not only inherited from a dependency
+		id, title := name, ""
+		for _, via := range vulnerability.Via {
+			var advisory struct {
+				Source int
+				URL    string
+				Title  string
+			}
+			if err := json.Unmarshal(via, &advisory); err == nil && advisory.URL != "" {
+				// GitHub advisory URLs end with the GHSA identifier
+				id, title = path.Base(advisory.URL), advisory.Title
+				break
+			}
+		}
+		findings = append(findings, Finding{
+			ID:       id,
+			Severity: severity,
+			Path:     location,
+			Title:    title,
+		})
+	}
+	// Map iteration order is random
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Path < findings[j].Path
+	})
+	return findings, nil
+}
@@ -0,0 +1,80 @@
+package scan
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"strings"
+)
+
+// IgnoreList contains the identifiers and the paths of the findings to ignore
+type IgnoreList struct {
+	// Identifiers to ignore (CVE, GHSA, rule ID...)
+	IDs map[string]bool
+	// Glob patterns of the paths to ignore
+	Paths []string
+}
+
+// ReadIgnoreList reads an ignore file.
+//
+// Each non empty line contains either an identifier (like CVE-2021-44228) or,
+// when prefixed by "path:", a glob pattern for the paths to ignore. Lines
+// starting with # are comments.
+func ReadIgnoreList(file string) (*IgnoreList, error) {
+	reader, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	list := &IgnoreList{
+		IDs: make(map[string]bool),
+	}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if pattern, ok := strings.CutPrefix(line, "path:"); ok {
+			list.Paths = append(list.Paths, strings.TrimSpace(pattern))
+		} else {
+			list.IDs[line] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Ignores checks if a finding must be ignored
+func (list *IgnoreList) Ignores(finding Finding) bool {
+	if list == nil {
+		return false
+	}
+	if list.IDs[finding.ID] {
+		return true
+	}
+	for _, pattern := range list.Paths {
+		if matched, _ := path.Match(pattern, finding.Path); matched {
+			return true
+		}
+		// A pattern also ignores everything below it
+		if strings.HasPrefix(finding.Path, strings.TrimSuffix(pattern, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the findings which are not ignored
+func (list *IgnoreList) Filter(findings []Finding) []Finding {
+	var kept []Finding
+	for _, finding := range findings {
+		if !list.Ignores(finding) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
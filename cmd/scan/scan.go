@@ -0,0 +1,220 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CHML levels, as used by the CHML validation data type in Ontrack
+const (
+	Critical = "CRITICAL"
+	High     = "HIGH"
+	Medium   = "MEDIUM"
+	Low      = "LOW"
+)
+
+// Supported report formats
+const (
+	FormatSARIF           = "sarif"
+	FormatTrivy           = "trivy"
+	FormatGrype           = "grype"
+	FormatDependencyCheck = "dependency-check"
+	FormatNpmAudit        = "npm-audit"
+)
+
+// Formats lists all the supported report formats
+var Formats = []string{
+	FormatSARIF,
+	FormatTrivy,
+	FormatGrype,
+	FormatDependencyCheck,
+	FormatNpmAudit,
+}
+
+// Finding is a single issue reported by a scanner
+type Finding struct {
+	// Identifier of the issue (CVE, GHSA, rule ID...)
+	ID string
+	// CHML level of the issue
+	Severity string
+	// Path or package the issue was found in
+	Path string
+	// Short description of the issue
+	Title string
+}
+
+type parser func(buf []byte) ([]Finding, error)
+
+var parsers = map[string]parser{
+	FormatSARIF:           parseSARIF,
+	FormatTrivy:           parseTrivy,
+	FormatGrype:           parseGrype,
+	FormatDependencyCheck: parseDependencyCheck,
+	FormatNpmAudit:        parseNpmAudit,
+}
+
+// ParseReports parses all the given report files and returns the list of their findings.
+// If format is empty, the format of each file is detected from its content.
+func ParseReports(paths []string, format string) ([]Finding, error) {
+	var findings []Finding
+	for _, path := range paths {
+		fileFindings, err := ParseReport(path, format)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, fileFindings...)
+	}
+	return findings, nil
+}
+
+// ParseReport parses one report file and returns the list of its findings.
+// If format is empty, the format is detected from the content of the file.
+func ParseReport(path string, format string) ([]Finding, error) {
+	reader, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	buf, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		format, err = detectFormat(buf)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	parse, ok := parsers[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported report format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+
+	findings, err := parse(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return findings, nil
+}
+
+func detectFormat(buf []byte) (string, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &root); err != nil {
+		return "", fmt.Errorf("cannot detect the report format: %w", err)
+	}
+	has := func(key string) bool {
+		_, ok := root[key]
+		return ok
+	}
+	switch {
+	case has("runs") && (has("$schema") || has("version")):
+		return FormatSARIF, nil
+	case has("SchemaVersion") || has("ArtifactName"):
+		return FormatTrivy, nil
+	case has("matches") && has("descriptor"):
+		return FormatGrype, nil
+	case has("dependencies") && (has("reportSchema") || has("scanInfo")):
+		return FormatDependencyCheck, nil
+	case has("auditReportVersion") || has("advisories"):
+		return FormatNpmAudit, nil
+	}
+	return "", fmt.Errorf("cannot detect the report format, use one of %s explicitly", strings.Join(Formats, ", "))
+}
+
+// Severity maps a scanner severity to a CHML level.
+// Returns an empty string for informational severities, which are not counted.
+func Severity(value string) string {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "CRITICAL":
+		return Critical
+	case "HIGH", "ERROR":
+		return High
+	case "MEDIUM", "MODERATE", "WARNING":
+		return Medium
+	case "LOW", "NOTE":
+		return Low
+	default:
+		return ""
+	}
+}
+
+// cvssSeverity maps a CVSS score to a CHML level, using the same ranges as the CVSS v3 qualitative ratings
+func cvssSeverity(score float64) string {
+	switch {
+	case score >= 9.0:
+		return Critical
+	case score >= 7.0:
+		return High
+	case score >= 4.0:
+		return Medium
+	case score > 0:
+		return Low
+	default:
+		return ""
+	}
+}
+
+var severityRanks = map[string]int{
+	Critical: 0,
+	High:     1,
+	Medium:   2,
+	Low:      3,
+}
+
+// Summary counts the findings per CHML level
+func Summary(findings []Finding) (int, int, int, int) {
+	critical, high, medium, low := 0, 0, 0, 0
+	for _, finding := range findings {
+		switch finding.Severity {
+		case Critical:
+			critical++
+		case High:
+			high++
+		case Medium:
+			medium++
+		case Low:
+			low++
+		}
+	}
+	return critical, high, medium, low
+}
+
+// TopFindings returns at most count findings, the most severe first
+func TopFindings(findings []Finding, count int) []Finding {
+	sorted := make([]Finding, len(findings))
+	copy(sorted, findings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return severityRanks[sorted[i].Severity] < severityRanks[sorted[j].Severity]
+	})
+	if count < len(sorted) {
+		sorted = sorted[:count]
+	}
+	return sorted
+}
+
+// Description builds a one-line description of the most severe findings
+func Description(findings []Finding, count int) string {
+	top := TopFindings(findings, count)
+	if len(top) == 0 {
+		return ""
+	}
+	var items []string
+	for _, finding := range top {
+		item := fmt.Sprintf("%s %s", finding.Severity, finding.ID)
+		if finding.Path != "" {
+			item += " in " + finding.Path
+		}
+		items = append(items, item)
+	}
+	description := strings.Join(items, "; ")
+	if len(findings) > len(top) {
+		description += fmt.Sprintf(" (and %d more)", len(findings)-len(top))
+	}
+	return description
+}
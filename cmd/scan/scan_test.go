@@ -0,0 +1,92 @@
+package scan
+
+import (
+	"testing"
+)
+
+func TestScanParsing(t *testing.T) {
+
+	tests := []struct {
+		file     string
+		format   string
+		critical int
+		high     int
+		medium   int
+		low      int
+	}{
+		{"scan_reports/sarif.json", FormatSARIF, 1, 1, 0, 1},
+		{"scan_reports/trivy.json", FormatTrivy, 1, 2, 1, 0},
+		{"scan_reports/grype.json", FormatGrype, 1, 1, 0, 0},
+		{"scan_reports/dependency-check.json", FormatDependencyCheck, 2, 0, 1, 0},
+		{"scan_reports/npm-audit.json", FormatNpmAudit, 2, 0, 1, 1},
+	}
+
+	for _, test := range tests {
+		// Detected format
+		findings, err := ParseReport(test.file, "")
+		if err != nil {
+			t.Errorf("Error reading the %s report: %v", test.file, err)
+			continue
+		}
+		critical, high, medium, low := Summary(findings)
+		if critical != test.critical || high != test.high || medium != test.medium || low != test.low {
+			t.Errorf("%s - Expected: %d/%d/%d/%d, Actual: %d/%d/%d/%d", test.file,
+				test.critical, test.high, test.medium, test.low,
+				critical, high, medium, low)
+		}
+
+		// Explicit format
+		explicit, err := ParseReport(test.file, test.format)
+		if err != nil {
+			t.Errorf("Error reading the %s report as %s: %v", test.file, test.format, err)
+		} else if len(explicit) != len(findings) {
+			t.Errorf("%s - Expected: %d findings, Actual: %d", test.file, len(findings), len(explicit))
+		}
+	}
+}
+
+func TestScanIgnoreList(t *testing.T) {
+
+	list, err := ReadIgnoreList("scan_reports/scanignore")
+	if err != nil {
+		t.Fatalf("Error reading the ignore list: %v", err)
+	}
+
+	findings, err := ParseReports([]string{
+		"scan_reports/sarif.json",
+		"scan_reports/dependency-check.json",
+		"scan_reports/npm-audit.json",
+	}, "")
+	if err != nil {
+		t.Fatalf("Error reading the reports: %v", err)
+	}
+
+	critical, high, medium, low := Summary(list.Filter(findings))
+
+	if critical != 4 {
+		t.Errorf("Critical - Expected: 4, Actual: %v", critical)
+	}
+	if high != 1 {
+		t.Errorf("High - Expected: 1, Actual: %v", high)
+	}
+	if medium != 2 {
+		t.Errorf("Medium - Expected: 2, Actual: %v", medium)
+	}
+	if low != 0 {
+		t.Errorf("Low - Expected: 0, Actual: %v", low)
+	}
+}
+
+func TestScanDescription(t *testing.T) {
+
+	findings, err := ParseReport("scan_reports/trivy.json", "")
+	if err != nil {
+		t.Fatalf("Error reading the report: %v", err)
+	}
+
+	expected := "CRITICAL CVE-2021-3711 in alpine:3.14 (alpine 3.14.2):libssl1.1; " +
+		"HIGH CVE-2021-3712 in alpine:3.14 (alpine 3.14.2):libssl1.1 (and 2 more)"
+	if actual := Description(findings, 2); actual != expected {
+		t.Errorf("Description - Expected: %s, Actual: %s", expected, actual)
+	}
+}
@@ -22,12 +22,12 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"encoding/json"
 	"errors"
 
 	"github.com/spf13/cobra"
 
 	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
 	config "ontrack-cli/config"
 )
 
@@ -43,20 +43,82 @@ The simplest form is:
 
 where 'STATUS' is a valid Ontrack status, like 'PASSED', 'WARNING' or 'FAILED'.
 
+The build can also be given by reference: '@latest' for the last build of the branch, '@latest:GOLD' for the last
+build promoted to GOLD, '@commit:<sha>' or '@release:<release>' for the build having this commit or release,
+and '#<id>' for the build having this ID.
+
 In case there is some data to be passed to the validation:
 
 	ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION \
 		--data-type net.nemerosa.ontrack.extension.general.validation.TestSummaryValidationDataType \
-		--data {passed: 1, skipped: 2, failed: 3}
+		--data '{passed: 1, skipped: 2, failed: 3}'
 
 In this case, there is no need to pass the status but it could still be forced using the '-s STATUS' flag.
 
+The data type can also be given using its alias, like 'tests', 'chml', 'percentage', 'number', 'fraction',
+'metrics' or 'text' (see 'ontrack-cli vs data-types'). The data is checked against the schema of its type
+before being sent.
+
 Note that subcommands, dedicated to the most common types are also available. For example:
 
     ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION tests --passed 1 --skipped 2 --failed 3
 
+Several validations can be recorded in one request using a YAML manifest:
+
+    ontrack-cli validate -p PROJECT -b BRANCH -n BUILD --manifest validations.yaml
+
+where each entry names the validation stamp and either a status, some typed data
+(tests, chml, percentage, metrics) or some report files (junit, scan, metrics-files):
+
+    validations:
+      - validation: lint
+        status: PASSED
+      - validation: unit
+        junit: build/test-results/**/*.xml
+      - validation: security
+        scan:
+          files:
+            - trivy.json
+      - validation: coverage
+        percentage: 82
+
+When a CI job is retried, the same validation may be recorded twice. This can be avoided using:
+
+* '--if-absent' - the validation is recorded only if the build has no run yet for the validation stamp
+* '--idempotency-key' - the validation is recorded only if the build has no run with the same key. Without
+  a value, the key is computed from the run info trigger data and the validation stamp. An explicit key
+  can be given using '--idempotency-key=KEY' and is recorded in the description of the run.
+* '--if-status-differs' - the validation is recorded only if the status of the last run is different. It
+  requires an explicit status, given by '--status' or by the 'status' of each manifest entry, and is not
+  available on the subcommands, whose status is computed by Ontrack from the data.
+
+Skipped validations are reported on the output.
+
 Type 'ontrack-cli validate --help' to get a list of all options.
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := resolveBuildFlag(cmd); err != nil {
+			return err
+		}
+		// The validation stamp is not needed when using a manifest
+		if cmd.Flags().Lookup("manifest") != nil {
+			manifest, err := cmd.Flags().GetString("manifest")
+			if err != nil {
+				return err
+			}
+			if manifest != "" {
+				return nil
+			}
+		}
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+		if validation == "" {
+			return errors.New(`required flag(s) "validation" not set`)
+		}
+		return nil
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project, err := cmd.Flags().GetString("project")
 		if err != nil {
@@ -74,6 +136,15 @@ Type 'ontrack-cli validate --help' to get a list of all options.
 			return err
 		}
 
+		// Several validations at once
+		manifest, err := cmd.Flags().GetString("manifest")
+		if err != nil {
+			return err
+		}
+		if manifest != "" {
+			return validateWithManifest(cmd, project, branch, build, manifest)
+		}
+
 		validation, err := cmd.Flags().GetString("validation")
 		if err != nil {
 			return err
@@ -93,6 +164,7 @@ Type 'ontrack-cli validate --help' to get a list of all options.
 		if err != nil {
 			return err
 		}
+		dataType = datatypes.Resolve(dataType)
 
 		data, err := cmd.Flags().GetString("data")
 		if err != nil {
@@ -174,8 +246,8 @@ Type 'ontrack-cli validate --help' to get a list of all options.
 
 		// Data variable
 		if data != "" {
-			var dataJson interface{}
-			if err := json.Unmarshal([]byte(data), &dataJson); err != nil {
+			dataJson, err := datatypes.ValidateData(dataType, data)
+			if err != nil {
 				return err
 			}
 			variables["data"] = dataJson
@@ -192,6 +264,19 @@ Type 'ontrack-cli validate --help' to get a list of all options.
 			return err
 		}
 
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, validation, status, runInfo)
+		if err != nil || skip {
+			return err
+		}
+		description, err = IdempotentDescription(cmd, description)
+		if err != nil {
+			return err
+		}
+		if description != "" {
+			variables["description"] = description
+		}
+
 		// Mutation payload
 		var payload struct {
 			CreateValidationRun struct {
@@ -225,21 +310,25 @@ func init() {
 	// and all subcommands, e.g.:
 	validateCmd.PersistentFlags().StringP("project", "p", "", "Name of the project")
 	validateCmd.PersistentFlags().StringP("branch", "b", "", "Name of the branch")
-	validateCmd.PersistentFlags().StringP("build", "n", "", "Name of the build")
+	validateCmd.PersistentFlags().StringP("build", "n", "", buildRefUsage)
 	validateCmd.PersistentFlags().StringP("validation", "v", "", "Name of the validation stamp")
 	validateCmd.PersistentFlags().StringP("description", "d", "", "Description for the validation")
 
 	validateCmd.MarkPersistentFlagRequired("project")
 	validateCmd.MarkPersistentFlagRequired("branch")
 	validateCmd.MarkPersistentFlagRequired("build")
-	validateCmd.MarkPersistentFlagRequired("validation")
 
 	// Run info arguments
 	InitRunInfoCommandFlags(validateCmd)
 
+	// Idempotency arguments
+	InitIdempotencyCommandFlags(validateCmd)
+
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	validateCmd.Flags().StringP("status", "s", "", "ID of the status (required if no data)")
-	validateCmd.Flags().StringP("data-type", "t", "", "FQCN of the validation data type")
+	validateCmd.Flags().Bool("if-status-differs", false, "Records the validation only if the status of the last run for the validation stamp is different (requires an explicit status)")
+	validateCmd.Flags().StringP("data-type", "t", "", "FQCN or alias of the validation data type")
 	validateCmd.Flags().StringP("data", "o", "", "JSON representation of the validation data")
+	validateCmd.Flags().String("manifest", "", "Path to a YAML file listing several validations to record in one request")
 }
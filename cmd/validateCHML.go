@@ -90,74 +90,58 @@ For example:
 			return err
 		}
 
+		// Quality gates
+		gates, err := GetGates(cmd, chmlGateNames)
+		if err != nil {
+			return err
+		}
+
+		// Values for the quality gates
+		values := map[string]float64{
+			"critical": float64(critical),
+			"high":     float64(high),
+			"medium":   float64(medium),
+			"low":      float64(low),
+		}
+
 		// Get the configuration
 		cfg, err := config.GetSelectedConfiguration()
 		if err != nil {
 			return err
 		}
 
-		// Mutation payload
-		var payload struct {
-			ValidateBuildWithCHML struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-
-		// Runs the mutation
-		if err := client.GraphQLCall(cfg, `
-			mutation ValidateBuildWithCHML(
-				$project: String!,
-				$branch: String!,
-				$build: String!,
-				$validationStamp: String!,
-				$description: String!,
-				$runInfo: RunInfoInput,
-				$critical: Int!,
-				$high: Int!,
-				$medium: Int!,
-				$low: Int!
-			) {
-				validateBuildWithCHML(input: {
-					project: $project,
-					branch: $branch,
-					build: $build,
-					validation: $validationStamp,
-					description: $description,
-					runInfo: $runInfo,
-					critical: $critical,
-					high: $high,
-					medium: $medium,
-					low: $low
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":         project,
-			"branch":          branch,
-			"build":           build,
-			"validationStamp": validation,
-			"description":     description,
-			"runInfo":         runInfo,
-			"critical":        critical,
-			"high":            high,
-			"medium":          medium,
-			"low":             low,
-		}, &payload); err != nil {
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, validation, "", runInfo)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return CheckGates(cmd, gates, values)
+		}
+		description, err = IdempotentDescription(cmd, description)
+		if err != nil {
 			return err
 		}
 
-		// Checks for errors
-		if err := client.CheckDataErrors(payload.ValidateBuildWithCHML.Errors); err != nil {
+		// Call
+		if err := client.ValidateWithCHML(
+			cfg,
+			project,
+			branch,
+			build,
+			validation,
+			description,
+			runInfo,
+			critical,
+			high,
+			medium,
+			low,
+		); err != nil {
 			return err
 		}
 
-		// OK
-		return nil
+		// Checks the quality gates
+		return CheckGates(cmd, gates, values)
 	},
 }
 
@@ -177,6 +161,8 @@ func init() {
 	validateCHMLCmd.Flags().Int("medium", 0, "Number of medium issues")
 	validateCHMLCmd.Flags().Int("low", 0, "Number of low issues")
 
+	InitGateCommandFlags(validateCHMLCmd, "critical>=1")
+
 	// Run info arguments
 	InitRunInfoCommandFlags(validateCHMLCmd)
 }
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bytes"
+	"slices"
+	"text/template"
+
 	"github.com/spf13/cobra"
 
 	client "ontrack-cli/client"
+	"ontrack-cli/cmd/gates"
 	"ontrack-cli/cmd/junit"
 	config "ontrack-cli/config"
 )
@@ -16,6 +21,23 @@ var validateJUnitTestsCmd = &cobra.Command{
 For example:
 
     ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION junit --pattern "**/results/*.xml"
+
+The results can be split into one validation per test suite, per package or per directory, using
+the '--split-by suite|package|directory' option:
+
+    ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v tests junit \
+        --pattern "services/*/build/test-results/test/*.xml" \
+        --split-by directory
+
+When splitting by directory, the name of each group is the first directory after the part of the pattern
+without any wildcard - in the example above, the name of each service.
+
+The name of the validation stamp for each group is computed using the '--stamp-template' Go template, where
+'.Validation' is the name given by '--validation' and '.Module' is the name of the group. The default
+template is '{{.Validation}}-{{.Module}}'.
+
+The validation stamps which do not exist yet are created as tests validation stamps, and all the validations
+are recorded in one request.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project, err := cmd.Flags().GetString("project")
@@ -54,20 +76,57 @@ For example:
 			return err
 		}
 
-		// Get the configuration
-		cfg, err := config.GetSelectedConfiguration()
+		splitBy, err := cmd.Flags().GetString("split-by")
+		if err != nil {
+			return err
+		}
+
+		// Quality gates
+		gates, err := GetGates(cmd, testGateNames)
 		if err != nil {
 			return err
 		}
 
+		// One validation per group
+		if splitBy != "" {
+			return validateJUnitSplit(cmd, project, branch, build, validation, description, runInfo, pattern, splitBy, gates)
+		}
+
 		// Parsing of JUnit test reports
 		passed, skipped, failed, err := junit.GetSummaryJUnitTestReports(pattern)
 		if err != nil {
 			return err
 		}
 
+		// Values for the quality gates
+		values := map[string]float64{
+			"passed":  float64(passed),
+			"skipped": float64(skipped),
+			"failed":  float64(failed),
+			"total":   float64(passed + skipped + failed),
+		}
+
+		// Get the configuration
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, validation, "", runInfo)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return CheckGates(cmd, gates, values)
+		}
+		description, err = IdempotentDescription(cmd, description)
+		if err != nil {
+			return err
+		}
+
 		// Call
-		return client.ValidateWithTests(
+		if err := client.ValidateWithTests(
 			cfg,
 			project,
 			branch,
@@ -78,13 +137,146 @@ For example:
 			passed,
 			skipped,
 			failed,
-		)
+		); err != nil {
+			return err
+		}
+
+		// Checks the quality gates
+		return CheckGates(cmd, gates, values)
 	},
 }
 
+func validateJUnitSplit(
+	cmd *cobra.Command,
+	project string,
+	branch string,
+	build string,
+	validation string,
+	description string,
+	runInfo *client.RunInfo,
+	pattern string,
+	splitBy string,
+	gates []gates.Gate,
+) error {
+	stampTemplate, err := cmd.Flags().GetString("stamp-template")
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New("stamp").Parse(stampTemplate)
+	if err != nil {
+		return err
+	}
+
+	warningIfSkipped, err := cmd.Flags().GetBool("warning-if-skipped")
+	if err != nil {
+		return err
+	}
+
+	// Parsing & grouping of JUnit test reports
+	reports, err := junit.GetJUnitTestReports(pattern)
+	if err != nil {
+		return err
+	}
+	groups, err := junit.GroupJUnitTestReports(pattern, reports, splitBy)
+	if err != nil {
+		return err
+	}
+
+	// Get the configuration
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return err
+	}
+
+	// Existing validation stamps
+	existingStamps, err := client.GetValidationStampNames(cfg, project, branch)
+	if err != nil {
+		return err
+	}
+
+	// Explicit idempotency key
+	description, err = IdempotentDescription(cmd, description)
+	if err != nil {
+		return err
+	}
+
+	// One setup (if needed) and one validation per group
+	batch := client.NewBatch()
+	passed, skipped, failed := 0, 0, 0
+	for _, group := range groups {
+		passed += group.Passed
+		skipped += group.Skipped
+		failed += group.Failed
+
+		var name bytes.Buffer
+		if err := tmpl.Execute(&name, struct {
+			Validation string
+			Module     string
+		}{
+			Validation: validation,
+			Module:     group.Module,
+		}); err != nil {
+			return err
+		}
+		stamp := NormalizeValidationStampName(name.String())
+
+		if slices.Contains(existingStamps, stamp) {
+			skip, err := SkipValidation(cmd, cfg, project, branch, build, stamp, "", runInfo)
+			if err != nil {
+				return err
+			}
+			if skip {
+				continue
+			}
+		} else {
+			existingStamps = append(existingStamps, stamp)
+			batch.Add("Setup of "+stamp, "setupTestSummaryValidationStamp",
+				client.BatchArg{Name: "project", Type: "String!", Value: project},
+				client.BatchArg{Name: "branch", Type: "String!", Value: branch},
+				client.BatchArg{Name: "validation", Type: "String!", Value: stamp},
+				client.BatchArg{Name: "warningIfSkipped", Type: "Boolean!", Value: warningIfSkipped},
+			)
+		}
+
+		batch.Add("Validation of "+stamp, "validateBuildWithTests",
+			client.BatchArg{Name: "project", Type: "String!", Value: project},
+			client.BatchArg{Name: "branch", Type: "String!", Value: branch},
+			client.BatchArg{Name: "build", Type: "String!", Value: build},
+			client.BatchArg{Name: "validation", Type: "String!", Value: stamp},
+			client.BatchArg{Name: "description", Type: "String!", Value: description},
+			client.BatchArg{Name: "runInfo", Type: "RunInfoInput", Value: runInfo},
+			client.BatchArg{Name: "passed", Type: "Int!", Value: group.Passed},
+			client.BatchArg{Name: "skipped", Type: "Int!", Value: group.Skipped},
+			client.BatchArg{Name: "failed", Type: "Int!", Value: group.Failed},
+		)
+	}
+
+	// Call
+	batchErrors, err := batch.Run(cfg)
+	if err != nil {
+		return err
+	}
+	if err := batch.CheckErrors(batchErrors); err != nil {
+		return err
+	}
+
+	// Checks the quality gates on the totals
+	return CheckGates(cmd, gates, map[string]float64{
+		"passed":  float64(passed),
+		"skipped": float64(skipped),
+		"failed":  float64(failed),
+		"total":   float64(passed + skipped + failed),
+	})
+}
+
 func init() {
 	validateCmd.AddCommand(validateJUnitTestsCmd)
 	validateJUnitTestsCmd.Flags().String("pattern", "", "Pattern (glob) to the JUnit XML tests")
+	validateJUnitTestsCmd.Flags().String("split-by", "", "Records one validation per group of test suites: suite, package or directory")
+	validateJUnitTestsCmd.Flags().String("stamp-template", "{{.Validation}}-{{.Module}}", "Template for the names of the validation stamps when using --split-by")
+	validateJUnitTestsCmd.Flags().Bool("warning-if-skipped", false, "When using --split-by, configures the created validation stamps to fail if there are some skipped tests")
+	InitGateCommandFlags(validateJUnitTestsCmd, "failed>0")
+
 	// Run info arguments
 	InitRunInfoCommandFlags(validateJUnitTestsCmd)
 }
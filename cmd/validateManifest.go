@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/junit"
+	"ontrack-cli/cmd/metrics"
+	"ontrack-cli/cmd/scan"
+	config "ontrack-cli/config"
+)
+
+// List of validations to record in one request
+type validationManifest struct {
+	Validations []validationManifestEntry `yaml:"validations"`
+}
+
+// One validation in a manifest. Besides the validation stamp, only one type
+// of data must be given.
+type validationManifestEntry struct {
+	Validation  string `yaml:"validation"`
+	Description string `yaml:"description"`
+	// Status only
+	Status string `yaml:"status"`
+	// Typed data
+	Tests *struct {
+		Passed  int `yaml:"passed"`
+		Skipped int `yaml:"skipped"`
+		Failed  int `yaml:"failed"`
+	} `yaml:"tests"`
+	CHML *struct {
+		Critical int `yaml:"critical"`
+		High     int `yaml:"high"`
+		Medium   int `yaml:"medium"`
+		Low      int `yaml:"low"`
+	} `yaml:"chml"`
+	Percentage *int               `yaml:"percentage"`
+	Metrics    map[string]float64 `yaml:"metrics"`
+	// Report files
+	JUnit string `yaml:"junit"`
+	Scan  *struct {
+		Files  []string `yaml:"files"`
+		Format string   `yaml:"format"`
+		Ignore string   `yaml:"ignore"`
+	} `yaml:"scan"`
+	MetricsFiles []string `yaml:"metrics-files"`
+}
+
+// readValidationManifest parses a manifest of validations and checks its entries
+func readValidationManifest(file string) (*validationManifest, error) {
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var manifest validationManifest
+	if err := yaml.UnmarshalStrict(buf, &manifest); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for index, entry := range manifest.Validations {
+		if err := entry.check(); err != nil {
+			return nil, fmt.Errorf("%s: validations[%d]: %w", file, index, err)
+		}
+	}
+	return &manifest, nil
+}
+
+func (entry validationManifestEntry) check() error {
+	if entry.Validation == "" {
+		return errors.New("validation is required")
+	}
+	var types []string
+	if entry.Status != "" {
+		types = append(types, "status")
+	}
+	if entry.Tests != nil {
+		types = append(types, "tests")
+	}
+	if entry.CHML != nil {
+		types = append(types, "chml")
+	}
+	if entry.Percentage != nil {
+		types = append(types, "percentage")
+	}
+	if len(entry.Metrics) > 0 || len(entry.MetricsFiles) > 0 {
+		types = append(types, "metrics")
+	}
+	if entry.JUnit != "" {
+		types = append(types, "junit")
+	}
+	if entry.Scan != nil {
+		types = append(types, "scan")
+	}
+	if len(types) != 1 {
+		return fmt.Errorf(
+			"%s: exactly one of status, tests, chml, percentage, metrics (or metrics-files), junit or scan is required, got [%s]",
+			entry.Validation,
+			strings.Join(types, ", "),
+		)
+	}
+	if entry.Scan != nil && len(entry.Scan.Files) == 0 {
+		return fmt.Errorf("%s: scan.files is required", entry.Validation)
+	}
+	return nil
+}
+
+// validateWithManifest records all the validations of a manifest in one request
+func validateWithManifest(cmd *cobra.Command, project string, branch string, build string, file string) error {
+	runInfo, err := GetRunInfo(cmd)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := readValidationManifest(file)
+	if err != nil {
+		return err
+	}
+
+	// Get the configuration
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return err
+	}
+
+	batch := client.NewBatch()
+	for _, entry := range manifest.Validations {
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, entry.Validation, entry.Status, runInfo)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+		description, err := IdempotentDescription(cmd, entry.Description)
+		if err != nil {
+			return err
+		}
+
+		if err := addManifestEntry(batch, project, branch, build, runInfo, entry, description); err != nil {
+			return fmt.Errorf("%s: %w", entry.Validation, err)
+		}
+	}
+
+	// Call
+	batchErrors, err := batch.Run(cfg)
+	if err != nil {
+		return err
+	}
+	return batch.CheckErrors(batchErrors)
+}
+
+// addManifestEntry adds the mutation for a validation of the manifest to the batch
+func addManifestEntry(
+	batch *client.Batch,
+	project string,
+	branch string,
+	build string,
+	runInfo *client.RunInfo,
+	entry validationManifestEntry,
+	description string,
+) error {
+	label := "Validation of " + entry.Validation
+
+	// Common arguments
+	args := []client.BatchArg{
+		{Name: "project", Type: "String!", Value: project},
+		{Name: "branch", Type: "String!", Value: branch},
+		{Name: "build", Type: "String!", Value: build},
+		{Name: "runInfo", Type: "RunInfoInput", Value: runInfo},
+	}
+
+	// Status only
+	if entry.Status != "" {
+		batch.Add(label, "createValidationRun", append(args,
+			client.BatchArg{Name: "validationStamp", Type: "String!", Value: entry.Validation},
+			client.BatchArg{Name: "validationRunStatus", Type: "String", Value: entry.Status},
+			client.BatchArg{Name: "description", Type: "String", Value: description},
+		)...)
+		return nil
+	}
+
+	args = append(args,
+		client.BatchArg{Name: "validation", Type: "String!", Value: entry.Validation},
+	)
+
+	switch {
+	case entry.Tests != nil:
+		addTestsManifestEntry(batch, label, args, description, entry.Tests.Passed, entry.Tests.Skipped, entry.Tests.Failed)
+	case entry.JUnit != "":
+		passed, skipped, failed, err := junit.GetSummaryJUnitTestReports(entry.JUnit)
+		if err != nil {
+			return err
+		}
+		addTestsManifestEntry(batch, label, args, description, passed, skipped, failed)
+	case entry.CHML != nil:
+		addCHMLManifestEntry(batch, label, args, description, entry.CHML.Critical, entry.CHML.High, entry.CHML.Medium, entry.CHML.Low)
+	case entry.Scan != nil:
+		findings, err := scan.ParseReports(entry.Scan.Files, entry.Scan.Format)
+		if err != nil {
+			return err
+		}
+		if entry.Scan.Ignore != "" {
+			ignoreList, err := scan.ReadIgnoreList(entry.Scan.Ignore)
+			if err != nil {
+				return err
+			}
+			findings = ignoreList.Filter(findings)
+		}
+		if topDescription := scan.Description(findings, 5); topDescription != "" {
+			description = strings.TrimSpace(description + "\n" + topDescription)
+		}
+		critical, high, medium, low := scan.Summary(findings)
+		addCHMLManifestEntry(batch, label, args, description, critical, high, medium, low)
+	case entry.Percentage != nil:
+		batch.Add(label, "validateBuildWithPercentage", append(args,
+			client.BatchArg{Name: "description", Type: "String!", Value: description},
+			client.BatchArg{Name: "value", Type: "Int!", Value: *entry.Percentage},
+		)...)
+	default:
+		var metricList []metric
+		for name, value := range entry.Metrics {
+			metricList = append(metricList, metric{
+				Name:  name,
+				Value: value,
+			})
+		}
+		sort.Slice(metricList, func(i, j int) bool {
+			return metricList[i].Name < metricList[j].Name
+		})
+		fileMetrics, err := metrics.ParseFiles(entry.MetricsFiles, "")
+		if err != nil {
+			return err
+		}
+		for _, item := range fileMetrics {
+			metricList = append(metricList, metric{
+				Name:  item.Name,
+				Value: item.Value,
+			})
+		}
+		batch.Add(label, "validateBuildWithMetrics", append(args,
+			client.BatchArg{Name: "description", Type: "String!", Value: description},
+			client.BatchArg{Name: "metrics", Type: "[MetricsEntryInput!]!", Value: metricList},
+		)...)
+	}
+	return nil
+}
+
+func addTestsManifestEntry(batch *client.Batch, label string, args []client.BatchArg, description string, passed int, skipped int, failed int) {
+	batch.Add(label, "validateBuildWithTests", append(args,
+		client.BatchArg{Name: "description", Type: "String!", Value: description},
+		client.BatchArg{Name: "passed", Type: "Int!", Value: passed},
+		client.BatchArg{Name: "skipped", Type: "Int!", Value: skipped},
+		client.BatchArg{Name: "failed", Type: "Int!", Value: failed},
+	)...)
+}
+
+func addCHMLManifestEntry(batch *client.Batch, label string, args []client.BatchArg, description string, critical int, high int, medium int, low int) {
+	batch.Add(label, "validateBuildWithCHML", append(args,
+		client.BatchArg{Name: "description", Type: "String!", Value: description},
+		client.BatchArg{Name: "critical", Type: "Int!", Value: critical},
+		client.BatchArg{Name: "high", Type: "Int!", Value: high},
+		client.BatchArg{Name: "medium", Type: "Int!", Value: medium},
+		client.BatchArg{Name: "low", Type: "Int!", Value: low},
+	)...)
+}
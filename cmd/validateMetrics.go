@@ -22,14 +22,12 @@ THE SOFTWARE.
 package cmd
 
 import (
-	"errors"
-	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	client "ontrack-cli/client"
+	"ontrack-cli/cmd/metrics"
 	config "ontrack-cli/config"
 )
 
@@ -46,6 +44,20 @@ For example:
 An alternative syntax is:
 
 	ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION metrics --metrics name1=value1,name2=value2
+
+Metrics can also be read from files:
+
+	ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION metrics --from-file bench.txt
+
+The following formats are supported, and detected from the file extension or content if '--file-format' is not set:
+
+* prometheus - Prometheus text exposition format, labels being kept in the name, like 'name{code=200}'
+* json - JSON object, nested objects being flattened using dots, like 'parent.child'
+* csv - two columns CSV (name, value), with an optional header line
+* gobench - output of 'go test -bench', one metric per benchmark and unit, like 'Parse.ns_per_op'
+
+The metrics read from files can be filtered using the '--include' and '--exclude' regular expressions
+and their names prefixed using '--prefix'.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		project, err := cmd.Flags().GetString("project")
@@ -84,14 +96,17 @@ An alternative syntax is:
 
 		// Adding from the `metric` flags
 		metricArgs, err := cmd.Flags().GetStringSlice("metric")
+		if err != nil {
+			return err
+		}
 		for _, value := range metricArgs {
-			name, metricValue, err := parseMetric(value)
+			parsed, err := metrics.ParseMetric(value)
 			if err != nil {
 				return err
 			}
 			metricList = append(metricList, metric{
-				Name:  name,
-				Value: metricValue,
+				Name:  parsed.Name,
+				Value: parsed.Value,
 			})
 		}
 
@@ -103,23 +118,61 @@ An alternative syntax is:
 		if metricsArg != "" {
 			metricsArgTokens := strings.Split(metricsArg, ",")
 			for _, value := range metricsArgTokens {
-				name, metricValue, err := parseMetric(value)
+				parsed, err := metrics.ParseMetric(value)
 				if err != nil {
 					return err
 				}
 				metricList = append(metricList, metric{
-					Name:  name,
-					Value: metricValue,
+					Name:  parsed.Name,
+					Value: parsed.Value,
 				})
 			}
 		}
 
+		// Adding from the files
+		files, err := cmd.Flags().GetStringArray("from-file")
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			fileMetrics, err := readMetricFiles(cmd, files)
+			if err != nil {
+				return err
+			}
+			metricList = append(metricList, fileMetrics...)
+		}
+
+		// Quality gates, on any metric
+		gates, err := GetGates(cmd, nil)
+		if err != nil {
+			return err
+		}
+
+		// Values for the quality gates
+		values := make(map[string]float64)
+		for _, item := range metricList {
+			values[item.Name] = item.Value
+		}
+
 		// Get the configuration
 		cfg, err := config.GetSelectedConfiguration()
 		if err != nil {
 			return err
 		}
 
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, validation, "", runInfo)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return CheckGates(cmd, gates, values)
+		}
+		description, err = IdempotentDescription(cmd, description)
+		if err != nil {
+			return err
+		}
+
 		// Mutation payload
 		var payload struct {
 			ValidateBuildWithMetrics struct {
@@ -171,25 +224,47 @@ An alternative syntax is:
 			return err
 		}
 
-		// OK
-		return nil
+		// Checks the quality gates
+		return CheckGates(cmd, gates, values)
 	},
 }
 
-func parseMetric(value string) (string, float64, error) {
-	re := regexp.MustCompile(`^(.+)=(\d+(\.\d+)?)$`)
-	match := re.FindStringSubmatch(value)
-	if match == nil {
-		return "", 0, errors.New("Metric " + value + " must match name=number[.number]")
+func readMetricFiles(cmd *cobra.Command, files []string) ([]metric, error) {
+	format, err := cmd.Flags().GetString("file-format")
+	if err != nil {
+		return nil, err
 	}
-
-	name := match[1]
-	metric, err := strconv.ParseFloat(match[2], 64)
+	include, err := cmd.Flags().GetString("include")
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := cmd.Flags().GetString("exclude")
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := cmd.Flags().GetString("prefix")
 	if err != nil {
-		return "", 0, err
+		return nil, err
 	}
 
-	return name, metric, nil
+	fileMetrics, err := metrics.ParseFiles(files, format)
+	if err != nil {
+		return nil, err
+	}
+	fileMetrics, err = metrics.Filter(fileMetrics, include, exclude)
+	if err != nil {
+		return nil, err
+	}
+	fileMetrics = metrics.Prefix(fileMetrics, prefix)
+
+	var metricList []metric
+	for _, item := range fileMetrics {
+		metricList = append(metricList, metric{
+			Name:  item.Name,
+			Value: item.Value,
+		})
+	}
+	return metricList, nil
 }
 
 func init() {
@@ -206,6 +281,12 @@ func init() {
 	// validateMetricsCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	validateMetricsCmd.Flags().StringSliceP("metric", "m", []string{}, "List of metric, each value being provided like 'name=value'")
 	validateMetricsCmd.Flags().String("metrics", "", "Comma-separated list of metric, each value being provided like 'name=value'")
+	validateMetricsCmd.Flags().StringArray("from-file", []string{}, "Path to a file containing metrics (can be repeated)")
+	validateMetricsCmd.Flags().String("file-format", "", "Format of the metric files ("+strings.Join(metrics.Formats, ", ")+"), detected if not set")
+	validateMetricsCmd.Flags().String("include", "", "Regular expression the names of the metrics read from files must match")
+	validateMetricsCmd.Flags().String("exclude", "", "Regular expression the names of the metrics read from files must not match")
+	validateMetricsCmd.Flags().String("prefix", "", "Prefix to add to the names of the metrics read from files")
+	InitGateCommandFlags(validateMetricsCmd, "latency_p99>250")
 
 	// Run info arguments
 	InitRunInfoCommandFlags(validateMetricsCmd)
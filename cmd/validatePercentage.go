@@ -76,12 +76,36 @@ For example:
 			return err
 		}
 
+		// Quality gates
+		gates, err := GetGates(cmd, percentageGateNames)
+		if err != nil {
+			return err
+		}
+
+		// Values for the quality gates
+		values := map[string]float64{
+			"value": float64(value),
+		}
+
 		// Get the configuration
 		cfg, err := config.GetSelectedConfiguration()
 		if err != nil {
 			return err
 		}
 
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, validation, "", runInfo)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return CheckGates(cmd, gates, values)
+		}
+		description, err = IdempotentDescription(cmd, description)
+		if err != nil {
+			return err
+		}
+
 		// Mutation payload
 		var payload struct {
 			ValidateBuildWithPercentage struct {
@@ -133,8 +157,8 @@ For example:
 			return err
 		}
 
-		// OK
-		return nil
+		// Checks the quality gates
+		return CheckGates(cmd, gates, values)
 	},
 }
 
@@ -151,6 +175,7 @@ func init() {
 	// is called directly, e.g.:
 	// validatePercentageCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 	validatePercentageCmd.Flags().Int("value", 0, "Percentage value")
+	InitGateCommandFlags(validatePercentageCmd, "value<80")
 
 	// Run info arguments
 	InitRunInfoCommandFlags(validatePercentageCmd)
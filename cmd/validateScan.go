@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/scan"
+	config "ontrack-cli/config"
+)
+
+var validateScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Validation with CHML data from a security scan report",
+	Long: `Validation with CHML data from a security scan report.
+
+For example:
+
+    ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION scan --file trivy.json
+
+The following report formats are supported and detected automatically:
+
+* SARIF 2.1 (the "security-severity" property is used when available)
+* Trivy JSON
+* Grype JSON
+* OWASP Dependency-Check JSON
+* npm audit --json
+
+The '--format' flag can be used to force the format of the reports.
+
+Several reports can be passed, their findings are added up:
+
+    ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION scan --file grype.json --file npm-audit.json
+
+Findings can be ignored using a file containing one identifier (like CVE-2021-44228) per line, or
+a path pattern prefixed by 'path:' (like 'path:test/*'):
+
+    ontrack-cli validate -p PROJECT -b BRANCH -n BUILD -v VALIDATION scan --file trivy.json --ignore .scanignore
+
+The most severe findings are appended to the description of the validation.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		description, err := cmd.Flags().GetString("description")
+		if err != nil {
+			return err
+		}
+
+		runInfo, err := GetRunInfo(cmd)
+		if err != nil {
+			return err
+		}
+
+		files, err := cmd.Flags().GetStringArray("file")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		ignore, err := cmd.Flags().GetString("ignore")
+		if err != nil {
+			return err
+		}
+
+		top, err := cmd.Flags().GetInt("top")
+		if err != nil {
+			return err
+		}
+
+		// Parsing of the reports
+		findings, err := scan.ParseReports(files, format)
+		if err != nil {
+			return err
+		}
+
+		// Ignored findings
+		if ignore != "" {
+			ignoreList, err := scan.ReadIgnoreList(ignore)
+			if err != nil {
+				return err
+			}
+			findings = ignoreList.Filter(findings)
+		}
+
+		// Counts & description
+		critical, high, medium, low := scan.Summary(findings)
+		if top > 0 {
+			if topDescription := scan.Description(findings, top); topDescription != "" {
+				description = strings.TrimSpace(description + "\n" + topDescription)
+			}
+		}
+
+		// Quality gates
+		gates, err := GetGates(cmd, chmlGateNames)
+		if err != nil {
+			return err
+		}
+
+		// Values for the quality gates
+		values := map[string]float64{
+			"critical": float64(critical),
+			"high":     float64(high),
+			"medium":   float64(medium),
+			"low":      float64(low),
+		}
+
+		// Get the configuration
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, validation, "", runInfo)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return CheckGates(cmd, gates, values)
+		}
+		description, err = IdempotentDescription(cmd, description)
+		if err != nil {
+			return err
+		}
+
+		// Call
+		if err := client.ValidateWithCHML(
+			cfg,
+			project,
+			branch,
+			build,
+			validation,
+			description,
+			runInfo,
+			critical,
+			high,
+			medium,
+			low,
+		); err != nil {
+			return err
+		}
+
+		// Checks the quality gates
+		return CheckGates(cmd, gates, values)
+	},
+}
+
+func init() {
+	validateCmd.AddCommand(validateScanCmd)
+	validateScanCmd.Flags().StringArrayP("file", "f", []string{}, "Path to a scan report (can be repeated)")
+	validateScanCmd.Flags().String("format", "", "Format of the reports ("+strings.Join(scan.Formats, ", ")+"), detected if not set")
+	validateScanCmd.Flags().String("ignore", "", "Path to a file listing the identifiers and paths to ignore")
+	validateScanCmd.Flags().Int("top", 5, "Number of most severe findings to put into the description (0 to disable)")
+
+	validateScanCmd.MarkFlagRequired("file")
+
+	InitGateCommandFlags(validateScanCmd, "critical>=1")
+
+	// Run info arguments
+	InitRunInfoCommandFlags(validateScanCmd)
+}
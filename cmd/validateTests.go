@@ -85,14 +85,41 @@ For example:
 			return err
 		}
 
+		// Quality gates
+		gates, err := GetGates(cmd, testGateNames)
+		if err != nil {
+			return err
+		}
+
+		// Values for the quality gates
+		values := map[string]float64{
+			"passed":  float64(passed),
+			"skipped": float64(skipped),
+			"failed":  float64(failed),
+			"total":   float64(passed + skipped + failed),
+		}
+
 		// Get the configuration
 		cfg, err := config.GetSelectedConfiguration()
 		if err != nil {
 			return err
 		}
 
+		// Idempotency
+		skip, err := SkipValidation(cmd, cfg, project, branch, build, validation, "", runInfo)
+		if err != nil {
+			return err
+		}
+		if skip {
+			return CheckGates(cmd, gates, values)
+		}
+		description, err = IdempotentDescription(cmd, description)
+		if err != nil {
+			return err
+		}
+
 		// Call
-		return client.ValidateWithTests(
+		if err := client.ValidateWithTests(
 			cfg,
 			project,
 			branch,
@@ -103,7 +130,12 @@ For example:
 			passed,
 			skipped,
 			failed,
-		)
+		); err != nil {
+			return err
+		}
+
+		// Checks the quality gates
+		return CheckGates(cmd, gates, values)
 	},
 }
 
@@ -123,6 +155,8 @@ func init() {
 	validateTestsCmd.Flags().Int("skipped", 0, "Number of skipped tests")
 	validateTestsCmd.Flags().Int("failed", 0, "Number of failed tests")
 
+	InitGateCommandFlags(validateTestsCmd, "failed>0")
+
 	// Run info arguments
 	InitRunInfoCommandFlags(validateTestsCmd)
 }
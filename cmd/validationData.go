@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FQCN of the validation data types provided by Ontrack
+const (
+	TestSummaryValidationDataType = "net.nemerosa.ontrack.extension.general.validation.TestSummaryValidationDataType"
+	CHMLValidationDataType        = "net.nemerosa.ontrack.extension.general.validation.CHMLValidationDataType"
+	MetricsValidationDataType     = "net.nemerosa.ontrack.extension.general.validation.MetricsValidationDataType"
+	PercentageValidationDataType  = "net.nemerosa.ontrack.extension.general.validation.ThresholdPercentageValidationDataType"
+	FractionValidationDataType    = "net.nemerosa.ontrack.extension.general.validation.FractionValidationDataType"
+	TextValidationDataType        = "net.nemerosa.ontrack.extension.general.validation.TextValidationDataType"
+)
+
+// FormatValidationData returns a short human representation of some validation data
+func FormatValidationData(dataType string, data interface{}) string {
+	if data == nil {
+		return ""
+	}
+	object, _ := data.(map[string]interface{})
+	switch dataType {
+	case TestSummaryValidationDataType:
+		if object != nil {
+			return fmt.Sprintf("passed=%v, skipped=%v, failed=%v", object["passed"], object["skipped"], object["failed"])
+		}
+	case CHMLValidationDataType:
+		if levels, ok := object["levels"].(map[string]interface{}); ok {
+			var items []string
+			for _, level := range []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"} {
+				if value, ok := levels[level]; ok {
+					items = append(items, fmt.Sprintf("%s=%v", strings.ToLower(level), value))
+				}
+			}
+			return strings.Join(items, ", ")
+		}
+	case MetricsValidationDataType:
+		if metrics, ok := object["metrics"].(map[string]interface{}); ok {
+			var items []string
+			for name, value := range metrics {
+				items = append(items, fmt.Sprintf("%s=%v", name, value))
+			}
+			sort.Strings(items)
+			return strings.Join(items, ", ")
+		}
+	case PercentageValidationDataType:
+		return fmt.Sprintf("%v%%", data)
+	case FractionValidationDataType:
+		if object != nil {
+			return fmt.Sprintf("%v/%v", object["numerator"], object["denominator"])
+		}
+	case TextValidationDataType:
+		if text, ok := data.(string); ok {
+			return text
+		}
+	}
+	// Default JSON representation
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Sprintf("%v", data)
+	}
+	return string(buf)
+}
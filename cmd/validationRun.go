@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// validationRunCmd represents the validationRun command
+var validationRunCmd = &cobra.Command{
+	Use:     "validation-run",
+	Aliases: []string{"vr"},
+	Short:   "Management of validation runs",
+	Long: `Management of validation runs.
+
+To list the validation runs of a build:
+
+    ontrack-cli validation-run list -p PROJECT -b BRANCH -n BUILD
+
+To display the details of a validation run:
+
+    ontrack-cli validation-run show ID
+`,
+}
+
+func init() {
+	rootCmd.AddCommand(validationRunCmd)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+// Number of runs among which the runs having a given status are looked for
+const validationRunStatusSearchCount = 500
+
+var validationRunListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the validation runs of a build",
+	Long: `Lists the validation runs of a build, the most recent first.
+
+	ontrack-cli validation-run list -p PROJECT -b BRANCH -n BUILD
+
+The list can be restricted to a validation stamp and/or to a status:
+
+	ontrack-cli validation-run list -p PROJECT -b BRANCH -n BUILD --validation VALIDATION --status FAILED
+
+When filtering on a status, the runs are looked for among the last 500 runs of the build at least, and
+then limited to '--count'.
+
+Use '--output json' or '--output yaml' to get all the details of the runs.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		status, err := cmd.Flags().GetString("status")
+		if err != nil {
+			return err
+		}
+
+		count, err := cmd.Flags().GetInt("count")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Build reference
+		build, err = ResolveBuild(cfg, project, branch, build)
+		if err != nil {
+			return err
+		}
+
+		// The status is not a criterion of the query, and must be filtered before limiting
+		limit := count
+		if status != "" {
+			limit = max(count, validationRunStatusSearchCount)
+		}
+		runs, err := client.GetValidationRuns(cfg, project, branch, build, validation, limit)
+		if err != nil {
+			return err
+		}
+
+		// Filter on the last status
+		var result = []client.ValidationRun{}
+		for _, run := range runs {
+			if (status == "" || run.LastStatus.StatusID.ID == status) && (count <= 0 || len(result) < count) {
+				result = append(result, run)
+			}
+		}
+
+		return PrintOutput(cmd, result, func(w io.Writer) error {
+			fmt.Fprintln(w, "ID\tVALIDATION\tSTATUS\tCREATION\tDATA")
+			for _, run := range result {
+				var data string
+				if run.Data != nil {
+					data = FormatValidationData(run.Data.Descriptor.ID, run.Data.Data)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+					run.ID,
+					run.ValidationStamp.Name,
+					run.LastStatus.StatusID.ID,
+					run.Creation.Time,
+					data,
+				)
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	validationRunCmd.AddCommand(validationRunListCmd)
+
+	validationRunListCmd.Flags().StringP("project", "p", "", "Name of the project")
+	validationRunListCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+	validationRunListCmd.Flags().StringP("build", "n", "", buildRefUsage)
+	validationRunListCmd.Flags().StringP("validation", "v", "", "Name of the validation stamp")
+	validationRunListCmd.Flags().StringP("status", "s", "", "ID of the last status of the runs, like PASSED or FAILED")
+	validationRunListCmd.Flags().Int("count", 50, "Maximum number of runs to return")
+	InitOutputCommandFlags(validationRunListCmd)
+
+	validationRunListCmd.MarkFlagRequired("project")
+	validationRunListCmd.MarkFlagRequired("branch")
+	validationRunListCmd.MarkFlagRequired("build")
+}
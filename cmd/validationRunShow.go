@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var validationRunShowCmd = &cobra.Command{
+	Use:   "show ID",
+	Short: "Displays a validation run",
+	Long: `Displays a validation run, with its status history, description, run info and data.
+
+	ontrack-cli validation-run show ID
+
+Use '--output json' or '--output yaml' to get the raw data.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("validation run ID must be a number: %s", args[0])
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		run, err := client.GetValidationRun(cfg, id)
+		if err != nil || run == nil {
+			return err
+		}
+
+		return PrintOutput(cmd, run, func(w io.Writer) error {
+			printValidationRun(w, run)
+			return nil
+		})
+	},
+}
+
+func printValidationRun(w io.Writer, run *client.ValidationRun) {
+	fmt.Fprintf(w, "ID:\t%d\n", run.ID)
+	fmt.Fprintf(w, "Build:\t%s/%s/%s\n", run.Build.Branch.Project.Name, run.Build.Branch.Name, run.Build.Name)
+	fmt.Fprintf(w, "Validation:\t%s\n", run.ValidationStamp.Name)
+	fmt.Fprintf(w, "Status:\t%s\n", run.LastStatus.StatusID.ID)
+	fmt.Fprintf(w, "Creation:\t%s by %s\n", run.Creation.Time, run.Creation.User)
+	if run.Description != "" {
+		fmt.Fprintf(w, "Description:\t%s\n", run.Description)
+	}
+	if run.Data != nil {
+		fmt.Fprintf(w, "Data type:\t%s\n", run.Data.Descriptor.ID)
+		fmt.Fprintf(w, "Data:\t%s\n", FormatValidationData(run.Data.Descriptor.ID, run.Data.Data))
+	}
+	if run.RunInfo != nil {
+		fmt.Fprintf(w, "Source:\t%s %s\n", run.RunInfo.SourceType, run.RunInfo.SourceURI)
+		fmt.Fprintf(w, "Trigger:\t%s %s\n", run.RunInfo.TriggerType, run.RunInfo.TriggerData)
+		fmt.Fprintf(w, "Run time:\t%ds\n", run.RunInfo.RunTime)
+	}
+	fmt.Fprintln(w, "Statuses:")
+	printValidationRunStatuses(w, run.ValidationRunStatuses)
+}
+
+func printValidationRunStatuses(w io.Writer, statuses []client.ValidationRunStatus) {
+	for _, status := range statuses {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n",
+			status.Creation.Time,
+			status.StatusID.ID,
+			status.Creation.User,
+			status.Description,
+		)
+	}
+}
+
+func init() {
+	validationRunCmd.AddCommand(validationRunShowCmd)
+	InitOutputCommandFlags(validationRunShowCmd)
+}
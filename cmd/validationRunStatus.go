@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var validationRunStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Changes the status of a validation run",
+	Long: `Changes the status of a validation run, with an optional comment.
+
+The validation run can be identified by its ID:
+
+	ontrack-cli validation-run status --id RUN --status INVESTIGATING --comment "Looking at it"
+
+or by its build and validation stamp, in which case the latest run is targeted:
+
+	ontrack-cli validation-run status -p PROJECT -b BRANCH -n BUILD -v VALIDATION --status EXPLAINED --comment "Flaky test"
+
+Typical statuses are INVESTIGATING, EXPLAINED, DEFECT, FIXED or FAILED.
+
+The resulting status history of the validation run is displayed.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := cmd.Flags().GetInt("id")
+		if err != nil {
+			return err
+		}
+
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		build, err := cmd.Flags().GetString("build")
+		if err != nil {
+			return err
+		}
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		status, err := cmd.Flags().GetString("status")
+		if err != nil {
+			return err
+		}
+
+		comment, err := cmd.Flags().GetString("comment")
+		if err != nil {
+			return err
+		}
+
+		if id == 0 && (project == "" || branch == "" || build == "" || validation == "") {
+			return errors.New("Either --id or all of --project, --branch, --build and --validation are required")
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		// Build reference
+		build, err = ResolveBuild(cfg, project, branch, build)
+		if err != nil {
+			return err
+		}
+
+		// Latest run for the build & validation stamp
+		if id == 0 {
+			run, err := client.GetLastValidationRun(cfg, project, branch, build, validation)
+			if err != nil {
+				return err
+			}
+			if run == nil {
+				if cfg.Disabled {
+					return nil
+				}
+				return fmt.Errorf("No validation run for %s on build %s", validation, build)
+			}
+			id = run.ID
+		}
+
+		if err := client.ChangeValidationRunStatus(cfg, id, status, comment); err != nil {
+			return err
+		}
+
+		// Resulting status history
+		run, err := client.GetValidationRun(cfg, id)
+		if err != nil || run == nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		printValidationRunStatuses(w, run.ValidationRunStatuses)
+		return w.Flush()
+	},
+}
+
+func init() {
+	validationRunCmd.AddCommand(validationRunStatusCmd)
+
+	validationRunStatusCmd.Flags().Int("id", 0, "ID of the validation run")
+	validationRunStatusCmd.Flags().StringP("project", "p", "", "Name of the project")
+	validationRunStatusCmd.Flags().StringP("branch", "b", "", "Name of the branch")
+	validationRunStatusCmd.Flags().StringP("build", "n", "", buildRefUsage)
+	validationRunStatusCmd.Flags().StringP("validation", "v", "", "Name of the validation stamp")
+	validationRunStatusCmd.Flags().StringP("status", "s", "", "ID of the new status, like INVESTIGATING, EXPLAINED or DEFECT")
+	validationRunStatusCmd.Flags().StringP("comment", "c", "", "Comment for the new status")
+
+	validationRunStatusCmd.MarkFlagRequired("status")
+}
@@ -22,6 +22,9 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/spf13/cobra"
 
 	client "ontrack-cli/client"
@@ -34,6 +37,22 @@ var validationStampCmd = &cobra.Command{
 	Aliases: []string{"validation", "vs"},
 	Short:   "Management of validation stamps",
 	Long:    `Management of validation stamps.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The data types do not depend on a branch
+		if cmd == validationStampDataTypesCmd {
+			return nil
+		}
+		for _, name := range []string{"project", "branch"} {
+			value, err := cmd.Flags().GetString(name)
+			if err != nil {
+				return err
+			}
+			if value == "" {
+				return fmt.Errorf(`required flag(s) "%s" not set`, name)
+			}
+		}
+		return nil
+	},
 	// Run: func(cmd *cobra.Command, args []string) {},
 }
 
@@ -48,9 +67,6 @@ func init() {
 	validationStampCmd.PersistentFlags().StringP("project", "p", "", "Name of the project")
 	validationStampCmd.PersistentFlags().StringP("branch", "b", "", "Name of the branch")
 
-	validationStampCmd.MarkPersistentFlagRequired("project")
-	validationStampCmd.MarkPersistentFlagRequired("branch")
-
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// validationStampCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
@@ -112,3 +128,200 @@ func SetupTestValidationStamp(
 
 	return client.CheckDataErrors(data.SetupTestSummaryValidationStamp.Errors)
 }
+
+// NormalizeValidationStampName adapts a name to fit Ontrack naming conventions for validation stamps
+func NormalizeValidationStampName(name string) string {
+	re := regexp.MustCompile("[^A-Za-z0-9\\._-]")
+	return re.ReplaceAllString(name, "-")
+}
+
+// Utility method to setup a "CHML" validation stamp
+func SetupCHMLValidationStamp(
+	project string,
+	branch string,
+	validation string,
+	description string,
+	warningLevel string,
+	warningValue int,
+	failedLevel string,
+	failedValue int,
+) error {
+
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		SetupCHMLValidationStamp struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+
+	err = client.GraphQLCall(cfg, `
+		mutation SetupCHMLValidationStamp(
+			$project: String!,
+			$branch: String!,
+			$validation: String!,
+			$description: String,
+			$warningLevel: CHML!,
+			$warningValue: Int!,
+			$failedLevel: CHML!,
+			$failedValue: Int!
+		) {
+			setupCHMLValidationStamp(input: {
+				project: $project,
+				branch: $branch,
+				validation: $validation,
+				description: $description,
+				warningLevel: {
+					level: $warningLevel,
+					value: $warningValue
+				},
+				failedLevel: {
+					level: $failedLevel,
+					value: $failedValue
+				}
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":      project,
+		"branch":       branch,
+		"validation":   validation,
+		"description":  description,
+		"warningLevel": warningLevel,
+		"warningValue": warningValue,
+		"failedLevel":  failedLevel,
+		"failedValue":  failedValue,
+	}, &data)
+
+	if err != nil {
+		return err
+	}
+
+	return client.CheckDataErrors(data.SetupCHMLValidationStamp.Errors)
+}
+
+// Utility method to setup a "percentage" validation stamp. The thresholds are optional.
+func SetupPercentageValidationStamp(
+	project string,
+	branch string,
+	validation string,
+	description string,
+	warning *int,
+	failure *int,
+	okIfGreater bool,
+) error {
+
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		SetupPercentageValidationStamp struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+
+	err = client.GraphQLCall(cfg, `
+		mutation SetupPercentageValidationStamp(
+			$project: String!,
+			$branch: String!,
+			$validation: String!,
+			$description: String,
+			$warning: Int,
+			$failure: Int,
+			$okIfGreater: Boolean!
+		) {
+			setupPercentageValidationStamp(input: {
+				project: $project,
+				branch: $branch,
+				validation: $validation,
+				description: $description,
+				warningThreshold: $warning,
+				failureThreshold: $failure,
+				okIfGreater: $okIfGreater
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":     project,
+		"branch":      branch,
+		"validation":  validation,
+		"description": description,
+		"warning":     warning,
+		"failure":     failure,
+		"okIfGreater": okIfGreater,
+	}, &data)
+
+	if err != nil {
+		return err
+	}
+
+	return client.CheckDataErrors(data.SetupPercentageValidationStamp.Errors)
+}
+
+// Utility method to setup a "metrics" validation stamp
+func SetupMetricsValidationStamp(
+	project string,
+	branch string,
+	validation string,
+	description string,
+) error {
+
+	cfg, err := config.GetSelectedConfiguration()
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		SetupMetricsValidationStamp struct {
+			Errors []struct {
+				Message string
+			}
+		}
+	}
+
+	err = client.GraphQLCall(cfg, `
+		mutation SetupMetricsValidationStamp(
+			$project: String!,
+			$branch: String!,
+			$validation: String!,
+			$description: String
+		) {
+			setupMetricsValidationStamp(input: {
+				project: $project,
+				branch: $branch,
+				validation: $validation,
+				description: $description
+			}) {
+				errors {
+					message
+				}
+			}
+		}
+	`, map[string]interface{}{
+		"project":     project,
+		"branch":      branch,
+		"validation":  validation,
+		"description": description,
+	}, &data)
+
+	if err != nil {
+		return err
+	}
+
+	return client.CheckDataErrors(data.SetupMetricsValidationStamp.Errors)
+}
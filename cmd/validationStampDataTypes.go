@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
+	config "ontrack-cli/config"
+)
+
+// Schema of a data type not built in the CLI
+const schemaUnknown = "unknown"
+
+// Description of a validation data type, with its schemas when known by the CLI
+type validationDataTypeInfo struct {
+	ID      string `json:"id" yaml:"id"`
+	Alias   string `json:"alias,omitempty" yaml:"alias,omitempty"`
+	Name    string `json:"name,omitempty" yaml:"name,omitempty"`
+	Feature string `json:"feature,omitempty" yaml:"feature,omitempty"`
+	Config  string `json:"config,omitempty" yaml:"config,omitempty"`
+	Data    string `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+var validationStampDataTypesCmd = &cobra.Command{
+	Use:   "data-types",
+	Short: "Lists the validation data types",
+	Long: `Lists the validation data types registered in Ontrack, with their alias and
+the schemas of their configuration and of their data.
+
+    ontrack-cli vs data-types
+
+The aliases can be used instead of the FQCN of the data types in the 'validate --data-type'
+and 'vs setup generic --data-type' commands.
+
+Ontrack returns the ID, name and feature of the data types, but not their schemas: the schemas
+are the ones built in the CLI for the data types provided by Ontrack, and are shown as 'unknown'
+for the other types. In a schema, optional fields are suffixed by '?'.
+
+When the configuration is disabled, the data types built in the CLI are listed instead.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		dataTypes, err := client.GetValidationDataTypes(cfg)
+		if err != nil {
+			return err
+		}
+
+		result := []validationDataTypeInfo{}
+		if cfg.Disabled {
+			// No call to Ontrack, using the types built in the CLI
+			fmt.Fprintln(os.Stderr, "The configuration is disabled, listing the data types built in the CLI.")
+			for _, dataType := range datatypes.DataTypes {
+				result = append(result, newValidationDataTypeInfo(dataType.ID))
+			}
+		} else {
+			for _, dataType := range dataTypes {
+				info := newValidationDataTypeInfo(dataType.ID)
+				info.Name = dataType.DisplayName
+				info.Feature = dataType.Feature.ID
+				result = append(result, info)
+			}
+		}
+
+		return PrintOutput(cmd, result, func(w io.Writer) error {
+			fmt.Fprintln(w, "ALIAS\tID\tNAME\tCONFIG\tDATA")
+			for _, info := range result {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					info.Alias,
+					info.ID,
+					info.Name,
+					info.Config,
+					info.Data,
+				)
+			}
+			return nil
+		})
+	},
+}
+
+func newValidationDataTypeInfo(id string) validationDataTypeInfo {
+	info := validationDataTypeInfo{
+		ID:     id,
+		Config: schemaUnknown,
+		Data:   schemaUnknown,
+	}
+	if dataType := datatypes.Find(id); dataType != nil {
+		info.Config = ""
+		info.Alias = dataType.Alias
+		if dataType.Config != nil {
+			info.Config = dataType.Config.String()
+		}
+		info.Data = dataType.Data.String()
+	}
+	return info
+}
+
+func init() {
+	validationStampCmd.AddCommand(validationStampDataTypesCmd)
+	InitOutputCommandFlags(validationStampDataTypesCmd)
+}
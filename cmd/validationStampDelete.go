@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var validationStampDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Deletes a validation stamp",
+	Long: `Deletes a validation stamp, together with all its validation runs.
+
+	ontrack-cli vs delete --project PROJECT --branch BRANCH --validation STAMP
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		stamp, err := client.GetValidationStamp(cfg, project, branch, validation)
+		if err != nil || stamp == nil {
+			return err
+		}
+
+		confirmed, err := Confirm(cmd, fmt.Sprintf("Delete the validation stamp %s of %s/%s, together with all its runs?", stamp.Name, project, branch))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return ErrCancelled
+		}
+
+		return client.DeleteValidationStamp(cfg, stamp.ID)
+	},
+}
+
+func init() {
+	validationStampCmd.AddCommand(validationStampDeleteCmd)
+
+	validationStampDeleteCmd.Flags().StringP("validation", "v", "", "Name of the validation stamp")
+	InitConfirmCommandFlags(validationStampDeleteCmd)
+	validationStampDeleteCmd.MarkFlagRequired("validation")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var validationStampImageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Sets the image of a validation stamp",
+	Long: `Uploads the image of a validation stamp.
+
+	ontrack-cli vs image --project PROJECT --branch BRANCH --validation STAMP --file icon.png
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		file, err := cmd.Flags().GetString("file")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		stamp, err := client.GetValidationStamp(cfg, project, branch, validation)
+		if err != nil || stamp == nil {
+			return err
+		}
+
+		return client.SetValidationStampImage(cfg, stamp.ID, file)
+	},
+}
+
+func init() {
+	validationStampCmd.AddCommand(validationStampImageCmd)
+
+	validationStampImageCmd.Flags().StringP("validation", "v", "", "Name of the validation stamp")
+	validationStampImageCmd.Flags().StringP("file", "f", "", "Path to the image file (PNG)")
+	validationStampImageCmd.MarkFlagRequired("validation")
+	validationStampImageCmd.MarkFlagRequired("file")
+}
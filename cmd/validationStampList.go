@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
+	config "ontrack-cli/config"
+)
+
+var validationStampListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the validation stamps of a branch",
+	Long: `Lists the validation stamps of a branch, with their data type and configuration.
+
+	ontrack-cli vs list --project PROJECT --branch BRANCH
+
+Use '--output json' or '--output yaml' to get the raw data.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		stamps, err := client.GetValidationStamps(cfg, project, branch, "")
+		if err != nil {
+			return err
+		}
+
+		return PrintOutput(cmd, stamps, func(w io.Writer) error {
+			fmt.Fprintln(w, "ID\tNAME\tDATA TYPE\tCONFIG")
+			for _, stamp := range stamps {
+				dataType, dataConfig := formatValidationStampDataType(&stamp)
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n",
+					stamp.ID,
+					stamp.Name,
+					dataType,
+					dataConfig,
+				)
+			}
+			return nil
+		})
+	},
+}
+
+// formatValidationStampDataType returns the alias (or FQCN) of the data type of a validation stamp
+// and its configuration in a compact form.
+func formatValidationStampDataType(stamp *client.ValidationStamp) (string, string) {
+	if stamp.DataType == nil {
+		return "", ""
+	}
+	dataType := stamp.DataType.Descriptor.ID
+	if known := datatypes.Find(dataType); known != nil {
+		dataType = known.Alias
+	}
+	var dataConfig string
+	if stamp.DataType.Config != nil {
+		dataConfig = datatypes.GraphQLLiteral(stamp.DataType.Config)
+	}
+	return dataType, dataConfig
+}
+
+func init() {
+	validationStampCmd.AddCommand(validationStampListCmd)
+	InitOutputCommandFlags(validationStampListCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var validationStampRenameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Renames a validation stamp",
+	Long: `Renames a validation stamp. Its description is kept unless a new one is given.
+
+	ontrack-cli vs rename --project PROJECT --branch BRANCH --validation STAMP --name NEW_NAME
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		name, err := cmd.Flags().GetString("name")
+		if err != nil {
+			return err
+		}
+
+		description, err := cmd.Flags().GetString("description")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		stamp, err := client.GetValidationStamp(cfg, project, branch, validation)
+		if err != nil || stamp == nil {
+			return err
+		}
+
+		if !cmd.Flags().Changed("description") {
+			description = stamp.Description
+		}
+
+		return client.UpdateValidationStamp(cfg, stamp.ID, name, description)
+	},
+}
+
+func init() {
+	validationStampCmd.AddCommand(validationStampRenameCmd)
+
+	validationStampRenameCmd.Flags().StringP("validation", "v", "", "Name of the validation stamp")
+	validationStampRenameCmd.Flags().String("name", "", "New name of the validation stamp")
+	validationStampRenameCmd.Flags().StringP("description", "d", "", "New description of the validation stamp")
+	validationStampRenameCmd.MarkFlagRequired("validation")
+	validationStampRenameCmd.MarkFlagRequired("name")
+}
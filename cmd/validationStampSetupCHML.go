@@ -28,9 +28,6 @@ import (
 
 	"regexp"
 	"strconv"
-
-	client "ontrack-cli/client"
-	config "ontrack-cli/config"
 )
 
 // validationStampSetupCHMLCmd represents the validationStampSetupCHML command
@@ -77,67 +74,16 @@ For example:
 			return err
 		}
 
-		cfg, err := config.GetSelectedConfiguration()
-		if err != nil {
-			return err
-		}
-
-		var data struct {
-			SetupCHMLValidationStamp struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-		if err := client.GraphQLCall(cfg, `
-			mutation SetupCHMLValidationStamp(
-				$project: String!,
-				$branch: String!,
-				$validation: String!,
-				$description: String,
-				$warningLevel: CHML!,
-				$warningValue: Int!,
-				$failedLevel: CHML!,
-				$failedValue: Int!
-			) {
-				setupCHMLValidationStamp(input: {
-					project: $project,
-					branch: $branch,
-					validation: $validation,
-					description: $description,
-					warningLevel: {
-						level: $warningLevel,
-						value: $warningValue
-					},
-					failedLevel: {
-						level: $failedLevel,
-						value: $failedValue
-					}
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":      project,
-			"branch":       branch,
-			"validation":   validation,
-			"description":  description,
-			"warningLevel": warningLevel,
-			"warningValue": warningValue,
-			"failedLevel":  failedLevel,
-			"failedValue":  failedValue,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetupCHMLValidationStamp.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return SetupCHMLValidationStamp(
+			project,
+			branch,
+			validation,
+			description,
+			warningLevel,
+			warningValue,
+			failedLevel,
+			failedValue,
+		)
 	},
 }
 
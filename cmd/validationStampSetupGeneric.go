@@ -25,6 +25,7 @@ import (
 	"github.com/spf13/cobra"
 
 	client "ontrack-cli/client"
+	"ontrack-cli/cmd/datatypes"
 	config "ontrack-cli/config"
 )
 
@@ -44,6 +45,16 @@ You can also associate a data type with it, using the JSON representation of the
         --data-type "net.nemerosa.ontrack.extension.general.validation.CHMLValidationDataType" \
         --data-config '{warningLevel: {level: "HIGH",value:1}, failedLevel:{level:"CRITICAL",value:1}}'
 
+The data type can also be given using its alias, like 'tests', 'chml', 'percentage', 'number', 'fraction',
+'metrics' or 'text'. The configuration, in JSON or using the GraphQL notation, is checked against the schema of the data type
+before being sent:
+
+    ontrack-cli vs setup generic --project PROJECT --branch BRANCH --validation STAMP \
+        --data-type chml \
+        --data-config '{warningLevel: {level: HIGH, value: 1}, failedLevel: {level: CRITICAL, value: 1}}'
+
+See 'ontrack-cli vs data-types' for the list of data types and of their schemas.
+
 Note that specific commands per type are also available, see 'ontrack-cli vs setup'.
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -78,6 +89,16 @@ Note that specific commands per type are also available, see 'ontrack-cli vs set
 			return err
 		}
 
+		// Aliases & checks of the configuration
+		dataType = datatypes.Resolve(dataType)
+		if dataTypeConfig != "" {
+			value, err := datatypes.ValidateConfig(dataType, dataTypeConfig)
+			if err != nil {
+				return err
+			}
+			dataTypeConfig = datatypes.GraphQLLiteral(value)
+		}
+
 		cfg, err := config.GetSelectedConfiguration()
 		if err != nil {
 			return err
@@ -104,7 +125,7 @@ func init() {
 	// and all subcommands, e.g.:
 	// validationStampSetupGenericCmd.PersistentFlags().String("foo", "", "A help for foo")
 
-	validationStampSetupGenericCmd.PersistentFlags().StringP("data-type", "t", "", "FQCN of the data type")
+	validationStampSetupGenericCmd.PersistentFlags().StringP("data-type", "t", "", "FQCN or alias of the data type")
 	validationStampSetupGenericCmd.PersistentFlags().StringP("data-config", "c", "", "JSON for the data type configuration")
 
 	// Cobra supports local flags which will only run when this command
@@ -22,9 +22,6 @@ THE SOFTWARE.
 package cmd
 
 import (
-	client "ontrack-cli/client"
-	config "ontrack-cli/config"
-
 	"github.com/spf13/cobra"
 )
 
@@ -60,51 +57,12 @@ For example:
 			return err
 		}
 
-		cfg, err := config.GetSelectedConfiguration()
-		if err != nil {
-			return err
-		}
-
-		var data struct {
-			SetupMetricsValidationStamp struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-		if err := client.GraphQLCall(cfg, `
-			mutation SetupMetricsValidationStamp(
-				$project: String!,
-				$branch: String!,
-				$validation: String!,
-				$description: String
-			) {
-				setupMetricsValidationStamp(input: {
-					project: $project,
-					branch: $branch,
-					validation: $validation,
-					description: $description
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":     project,
-			"branch":      branch,
-			"validation":  validation,
-			"description": description,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetupMetricsValidationStamp.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return SetupMetricsValidationStamp(
+			project,
+			branch,
+			validation,
+			description,
+		)
 	},
 }
 
@@ -22,9 +22,6 @@ THE SOFTWARE.
 package cmd
 
 import (
-	client "ontrack-cli/client"
-	config "ontrack-cli/config"
-
 	"github.com/spf13/cobra"
 )
 
@@ -89,60 +86,15 @@ For example:
 			return err
 		}
 
-		cfg, err := config.GetSelectedConfiguration()
-		if err != nil {
-			return err
-		}
-
-		var data struct {
-			SetupPercentageValidationStamp struct {
-				Errors []struct {
-					Message string
-				}
-			}
-		}
-		if err := client.GraphQLCall(cfg, `
-			mutation SetupPercentageValidationStamp(
-				$project: String!,
-				$branch: String!,
-				$validation: String!,
-				$description: String,
-				$warning: Int,
-				$failure: Int,
-				$okIfGreater: Boolean!
-			) {
-				setupPercentageValidationStamp(input: {
-					project: $project,
-					branch: $branch,
-					validation: $validation,
-					description: $description,
-					warningThreshold: $warning,
-					failureThreshold: $failure,
-					okIfGreater: $okIfGreater
-				}) {
-					errors {
-						message
-					}
-				}
-			}
-		`, map[string]interface{}{
-			"project":     project,
-			"branch":      branch,
-			"validation":  validation,
-			"description": description,
-			"warning":     warningValue,
-			"failure":     failureValue,
-			"okIfGreater": okIfGreater,
-		}, &data); err != nil {
-			return err
-		}
-
-		if err := client.CheckDataErrors(data.SetupPercentageValidationStamp.Errors); err != nil {
-			return err
-		}
-
-		// OK
-		return nil
+		return SetupPercentageValidationStamp(
+			project,
+			branch,
+			validation,
+			description,
+			warningValue,
+			failureValue,
+			okIfGreater,
+		)
 	},
 }
 
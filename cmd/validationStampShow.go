@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	client "ontrack-cli/client"
+	config "ontrack-cli/config"
+)
+
+var validationStampShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Displays a validation stamp",
+	Long: `Displays a validation stamp, with its data type and configuration.
+
+	ontrack-cli vs show --project PROJECT --branch BRANCH --validation STAMP
+
+Use '--output json' or '--output yaml' to get the raw data.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		project, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		branch, err := cmd.Flags().GetString("branch")
+		if err != nil {
+			return err
+		}
+		branch = NormalizeBranchName(branch)
+
+		validation, err := cmd.Flags().GetString("validation")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.GetSelectedConfiguration()
+		if err != nil {
+			return err
+		}
+
+		stamp, err := client.GetValidationStamp(cfg, project, branch, validation)
+		if err != nil || stamp == nil {
+			return err
+		}
+
+		return PrintOutput(cmd, stamp, func(w io.Writer) error {
+			fmt.Fprintf(w, "ID:\t%d\n", stamp.ID)
+			fmt.Fprintf(w, "Name:\t%s\n", stamp.Name)
+			if stamp.Description != "" {
+				fmt.Fprintf(w, "Description:\t%s\n", stamp.Description)
+			}
+			fmt.Fprintf(w, "Image:\t%t\n", stamp.Image)
+			if stamp.DataType != nil {
+				_, dataConfig := formatValidationStampDataType(stamp)
+				fmt.Fprintf(w, "Data type:\t%s\n", stamp.DataType.Descriptor.ID)
+				fmt.Fprintf(w, "Config:\t%s\n", dataConfig)
+			}
+			return nil
+		})
+	},
+}
+
+func init() {
+	validationStampCmd.AddCommand(validationStampShowCmd)
+
+	validationStampShowCmd.Flags().StringP("validation", "v", "", "Name of the validation stamp")
+	validationStampShowCmd.MarkFlagRequired("validation")
+
+	InitOutputCommandFlags(validationStampShowCmd)
+}